@@ -0,0 +1,57 @@
+package i2c
+
+import "encoding/binary"
+
+// regAddr16 encode 16-bit register address in byte order
+// configured with WithRegAddrOrder.
+func (v *I2C) regAddr16(reg uint16) []byte {
+	order := v.regAddrOrder
+	if order == nil {
+		order = binary.BigEndian
+	}
+	buf := make([]byte, 2)
+	order.PutUint16(buf, reg)
+	return buf
+}
+
+// ReadRegBytes16 read count of n byte's sequence from I2C-device
+// starting from 16-bit reg address (EEPROMs like 24LC256 and some
+// sensors use two-byte addressing). If enabled with WithCombinedReads,
+// address write and data read are done as one combined transaction.
+func (v *I2C) ReadRegBytes16(reg uint16, n int) ([]byte, int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	v.log.Debugf("[tx %d] Read %d bytes starting from reg 0x%04X...", tx, n, reg)
+	addr := v.regAddr16(reg)
+	buf := make([]byte, n)
+	if v.useCombined() {
+		c, err := v.readCombined(tx, addr, buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		return buf, c, nil
+	}
+	if _, err := v.sendBytes(tx, addr); err != nil {
+		return nil, 0, err
+	}
+	if v.turnaround > 0 {
+		v.clock.Sleep(v.turnaround)
+	}
+	c, err := v.readBytes(tx, buf)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, c, nil
+}
+
+// WriteRegBytes16 writes data to I2C-device starting from 16-bit
+// reg address: address and data are sent in one write message.
+// Mind page boundaries of EEPROMs, since address wraps within page.
+func (v *I2C) WriteRegBytes16(reg uint16, data []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf := append(v.regAddr16(reg), data...)
+	_, err := v.writeBytes16(nextTx(), buf)
+	return err
+}
@@ -0,0 +1,51 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestRegBytes16(t *testing.T) {
+	tests := []struct {
+		order binary.ByteOrder
+		addr  []byte
+	}{
+		{binary.BigEndian, []byte{0x12, 0x34}},
+		{binary.LittleEndian, []byte{0x34, 0x12}},
+	}
+	for _, test := range tests {
+		for _, combined := range []bool{false, true} {
+			opts := []Option{WithRegAddrOrder(test.order)}
+			if combined {
+				opts = append(opts, WithCombinedReads())
+			}
+			v, a, _ := newFake(t, 0x50, opts...)
+			c := a.chip(0x50)
+			c.addrLen = 2
+			if test.order == binary.LittleEndian {
+				// Fake chip decodes address big endian.
+				copy(c.regs[0x3412:], []byte{0xAA, 0xBB})
+			} else {
+				copy(c.regs[0x1234:], []byte{0xAA, 0xBB})
+			}
+			buf, n, err := v.ReadRegBytes16(0x1234, 2)
+			if err != nil || n != 2 || !bytes.Equal(buf, []byte{0xAA, 0xBB}) {
+				t.Errorf("%v combined=%v: read [% X], %d, %v", test.order, combined, buf, n, err)
+			}
+			if err := v.WriteRegBytes16(0x1234, []byte{0x01, 0x02}); err != nil {
+				t.Fatal(err)
+			}
+			w := a.writes()
+			if len(w) != 2 || !bytes.Equal(w[0], test.addr) ||
+				!bytes.Equal(w[1], append(append([]byte(nil), test.addr...), 0x01, 0x02)) {
+				t.Errorf("%v combined=%v: writes %X, want address [% X]",
+					test.order, combined, w, test.addr)
+			}
+			if ops := a.operations("write"); combined != ops[0].rdwr {
+				t.Errorf("%v combined=%v: address write in combined transaction %v",
+					test.order, combined, ops[0].rdwr)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package i2c
+
+// WithRegRead reads n bytes from I2C-device starting from reg address
+// and calls fn with them, while still holding connection lock, so fn can
+// combine them with further reads, consistent with the first one.
+//
+// Lock is not re-entrant: fn must not call methods, which acquire
+// the lock themselves (WithRegRead, for instance), otherwise it deadlocks.
+// Use raw WriteBytes and ReadBytes inside fn to talk to the device.
+// Error returned by fn is passed to the caller.
+func (v *I2C) WithRegRead(reg byte, n int, fn func([]byte) error) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf, _, err := v.readRegBytes(reg, n)
+	if err != nil {
+		return err
+	}
+	return fn(buf)
+}
+
+// ReadRegLoop performs count reads of n bytes from I2C-device starting
+// from reg address (register address is sent before every read),
+// calling fn with each result. All reads are done under one connection
+// lock, so loop stays coherent against other goroutines. Suitable for
+// burst sampling of devices, which reset internal register pointer
+// after each read. Loop stops on first error returned by fn.
+// Same re-entrancy rules as for WithRegRead apply to fn.
+func (v *I2C) ReadRegLoop(reg byte, n int, count int, fn func([]byte) error) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i := 0; i < count; i++ {
+		buf, _, err := v.readRegBytes(reg, n)
+		if err != nil {
+			return err
+		}
+		if err := fn(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
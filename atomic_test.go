@@ -0,0 +1,76 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithRegRead(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	copy(c.regs[0x10:], []byte{0x01, 0x02})
+	c.regs[0x20] = 0x03
+	var got []byte
+	err := v.WithRegRead(0x10, 2, func(buf []byte) error {
+		got = append(got, buf...)
+		// Further reads with raw transfers under the same lock.
+		if _, err := v.WriteBytes([]byte{0x20}); err != nil {
+			return err
+		}
+		b := make([]byte, 1)
+		if _, err := v.ReadBytes(b); err != nil {
+			return err
+		}
+		got = append(got, b...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte{0x01, 0x02, 0x03}) {
+		t.Errorf("got [% X], want [01 02 03]", got)
+	}
+
+	errFn := errors.New("decode failed")
+	if err := v.WithRegRead(0x10, 2, func([]byte) error { return errFn }); err != errFn {
+		t.Errorf("fn error not passed to caller: %v", err)
+	}
+	// Lock must be released after fn returns.
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadRegLoop(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	// Device resets pointer after each read: every
+	// read must be preceded by register address write.
+	a.chip(0x40).onRead = func(c *fakeChip) { c.regs[0x30]++ }
+	var got [][]byte
+	err := v.ReadRegLoop(0x30, 2, 3, func(buf []byte) error {
+		got = append(got, buf)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x01, 0x00}, {0x02, 0x00}, {0x03, 0x00}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fn called with %X, want %X", got, want)
+	}
+	if w := a.writes(); len(w) != 3 {
+		t.Errorf("register address written %d times, want 3", len(w))
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = v.ReadRegLoop(0x30, 1, 5, func([]byte) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("loop not stopped by fn error: %v after %d calls", err, calls)
+	}
+}
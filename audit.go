@@ -0,0 +1,64 @@
+package i2c
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// auditRecord is a single write audit entry.
+type auditRecord struct {
+	Time time.Time `json:"time"`
+	Bus  int       `json:"bus"`
+	Addr uint16    `json:"addr"`
+	Reg  byte      `json:"reg"`
+	Data string    `json:"data"`
+}
+
+// writeAudit serialize audit records to writer.
+type writeAudit struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// SetWriteAudit mirror every successful write to I2C-device (WriteBytes
+// and all WriteReg... helpers) to w, one JSON object per line:
+//
+//	{"time":"2018-05-01T12:00:00.5Z","bus":1,"addr":39,"reg":16,"data":"0a0b"}
+//
+// where addr is device address (7-bit or 10-bit one), reg is the first
+// byte sent (register address) and data is hex encoded rest of bytes. Reads (including register address
+// writes, which start register reads) aren't recorded. Unlike debug log,
+// audit can't be suppressed by log level, so it is suitable for
+// auditing device configuration changes. Nil w disable audit.
+func (v *I2C) SetWriteAudit(w io.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if w == nil {
+		v.audit = nil
+		return
+	}
+	v.audit = &writeAudit{w: w}
+}
+
+// auditWrite record successful write of buf, if audit enabled.
+func (v *I2C) auditWrite(buf []byte) {
+	a := v.audit
+	if a == nil || len(buf) == 0 {
+		return
+	}
+	rec := auditRecord{Time: v.clock.Now(), Bus: v.bus, Addr: v.GetAddr16(),
+		Reg: buf[0], Data: hex.EncodeToString(buf[1:])}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		v.log.Errorf("Can't marshal audit record: %v", err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		v.log.Errorf("Can't write audit record: %v", err)
+	}
+}
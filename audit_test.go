@@ -0,0 +1,65 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestWriteAudit(t *testing.T) {
+	v, _, clock := newFake(t, 0x27)
+	var out bytes.Buffer
+	v.SetWriteAudit(&out)
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.ReadRegBytes16(0x0100, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.WriteRegU16BE(0x10, 0x0A0B); err != nil {
+		t.Fatal(err)
+	}
+	// Single byte command is a write too.
+	if _, err := v.WriteBytes([]byte{0xFE}); err != nil {
+		t.Fatal(err)
+	}
+	want := []auditRecord{
+		{Time: clock.Now(), Bus: 1, Addr: 0x27, Reg: 0x10, Data: "0a0b"},
+		{Time: clock.Now(), Bus: 1, Addr: 0x27, Reg: 0xFE, Data: ""},
+	}
+	dec := json.NewDecoder(&out)
+	for i := 0; ; i++ {
+		var rec auditRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			if i != len(want) {
+				t.Errorf("%d records audited, want %d", i, len(want))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("record %d not parseable: %v", i, err)
+		}
+		if i >= len(want) || !rec.Time.Equal(want[i].Time) || rec.Bus != want[i].Bus ||
+			rec.Addr != want[i].Addr || rec.Reg != want[i].Reg || rec.Data != want[i].Data {
+			t.Errorf("record %d: %+v", i, rec)
+		}
+	}
+}
+
+func TestWriteAuditTenBit(t *testing.T) {
+	v, _ := newTenBitFake(t, 0x3A5)
+	var out bytes.Buffer
+	v.SetWriteAudit(&out)
+	if err := v.WriteRegU8(0x10, 0x01); err != nil {
+		t.Fatal(err)
+	}
+	var rec auditRecord
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Addr != 0x3A5 {
+		t.Errorf("audited address 0x%X, want 0x3A5", rec.Addr)
+	}
+}
@@ -0,0 +1,37 @@
+package i2c
+
+// UpdateReg reads byte from I2C-device register specified in reg,
+// replaces bits selected by mask with corresponding bits of value
+// and writes result back, leaving other bits intact. Read and write
+// are done under one connection lock, so concurrent callers
+// can't clobber each other's changes.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) UpdateReg(reg byte, mask, value byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.updateReg(reg, mask, value)
+}
+
+func (v *I2C) updateReg(reg byte, mask, value byte) error {
+	b, err := v.readRegU8(reg)
+	if err != nil {
+		return err
+	}
+	nb := b&^mask | value&mask
+	v.log.Debugf("Update reg 0x%0X with mask 0x%02X: 0x%02X -> 0x%02X", reg, mask, b, nb)
+	return v.writeRegU8(reg, nb)
+}
+
+// SetRegBits sets bits selected by mask in I2C-device register
+// specified in reg (read-modify-write under one connection lock).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) SetRegBits(reg byte, mask byte) error {
+	return v.UpdateReg(reg, mask, 0xFF)
+}
+
+// ClearRegBits clears bits selected by mask in I2C-device register
+// specified in reg (read-modify-write under one connection lock).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ClearRegBits(reg byte, mask byte) error {
+	return v.UpdateReg(reg, mask, 0)
+}
@@ -0,0 +1,65 @@
+package i2c
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestRegBits(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	c.regs[0x0A] = 0b1010_0101
+	steps := []struct {
+		name string
+		op   func() error
+		want byte
+	}{
+		{"set 0x0F", func() error { return v.SetRegBits(0x0A, 0x0F) }, 0b1010_1111},
+		{"clear 0x3C", func() error { return v.ClearRegBits(0x0A, 0x3C) }, 0b1000_0011},
+		// Value bits outside of mask are ignored.
+		{"update 0xF0 to 0x5A", func() error { return v.UpdateReg(0x0A, 0xF0, 0x5A) }, 0b0101_0011},
+		{"update 0x00", func() error { return v.UpdateReg(0x0A, 0x00, 0xFF) }, 0b0101_0011},
+	}
+	for _, step := range steps {
+		a.reset()
+		if err := step.op(); err != nil {
+			t.Fatal(err)
+		}
+		if c.regs[0x0A] != step.want {
+			t.Errorf("%s: register 0b%08b, want 0b%08b", step.name, c.regs[0x0A], step.want)
+		}
+		w := a.writes()
+		if last := w[len(w)-1]; !bytes.Equal(last, []byte{0x0A, step.want}) {
+			t.Errorf("%s: written [% X], want [0A %02X]", step.name, last, step.want)
+		}
+	}
+}
+
+func TestRegBitsConcurrent(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	quietLog(t)
+	var wg sync.WaitGroup
+	for bit := uint(0); bit < 8; bit++ {
+		wg.Add(1)
+		go func(mask byte) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := v.SetRegBits(0x0B, mask); err != nil {
+					t.Error(err)
+				}
+				if err := v.ClearRegBits(0x0B, mask); err != nil {
+					t.Error(err)
+				}
+			}
+			if err := v.SetRegBits(0x0B, mask); err != nil {
+				t.Error(err)
+			}
+		}(1 << bit)
+	}
+	wg.Wait()
+	// No update lost, since every read-modify-write is atomic.
+	if b := a.chip(0x40).regs[0x0B]; b != 0xFF {
+		t.Errorf("register 0x%02X, want 0xFF", b)
+	}
+}
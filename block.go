@@ -0,0 +1,43 @@
+package i2c
+
+import "encoding/binary"
+
+// ReadRegU16Block reads n unsigned words (16 bits) from I2C-device
+// starting from address specified in reg, decoding them
+// with byte order specified in order.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU16Block(reg byte, n int, order binary.ByteOrder) ([]uint16, error) {
+	out := make([]uint16, n)
+	if err := v.ReadRegU16BlockInto(reg, out, order); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadRegU16BlockInto reads len(out) unsigned words (16 bits)
+// from I2C-device starting from address specified in reg,
+// and decode them directly to out with byte order specified in order.
+// Internal byte buffer is reused between calls, so no memory allocated
+// in steady state, which is useful for high rate polling loops.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU16BlockInto(reg byte, out []uint16, order binary.ByteOrder) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	n := len(out) * 2
+	if cap(v.blockBuf) < n {
+		v.blockBuf = make([]byte, n)
+	}
+	buf := v.blockBuf[:n]
+	_, err := v.readReg(tx, reg, buf)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		out[i] = order.Uint16(buf[i*2:])
+	}
+	if v.log.enabled(DebugLevel) {
+		v.log.Debugf("[tx %d] Read %d U16 words from reg 0x%0X", tx, len(out), reg)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadRegU16BlockInto(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	copy(a.chip(0x40).regs[0x10:], []byte{0x12, 0x34, 0xAB, 0xCD, 0x00, 0xFF})
+	tests := []struct {
+		order binary.ByteOrder
+		want  []uint16
+	}{
+		{binary.BigEndian, []uint16{0x1234, 0xABCD, 0x00FF}},
+		{binary.LittleEndian, []uint16{0x3412, 0xCDAB, 0xFF00}},
+	}
+	for _, test := range tests {
+		out := make([]uint16, 3)
+		if err := v.ReadRegU16BlockInto(0x10, out, test.order); err != nil {
+			t.Fatalf("%v: %v", test.order, err)
+		}
+		for i := range out {
+			if out[i] != test.want[i] {
+				t.Errorf("%v: out[%d] = 0x%04X, want 0x%04X", test.order, i, out[i], test.want[i])
+			}
+		}
+	}
+}
+
+func BenchmarkReadRegU16Block(b *testing.B) {
+	v, a, _ := newFake(b, 0x40)
+	a.norecord = true
+	quietLog(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := v.ReadRegU16Block(0x10, 16, binary.BigEndian); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadRegU16BlockInto(b *testing.B) {
+	v, a, _ := newFake(b, 0x40)
+	a.norecord = true
+	quietLog(b)
+	out := make([]uint16, 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := v.ReadRegU16BlockInto(0x10, out, binary.BigEndian); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReadRegU16BlockIntoAllocs(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		var opts []Option
+		if combined {
+			opts = append(opts, WithCombinedReads())
+		}
+		v, a, _ := newFake(t, 0x40, opts...)
+		a.norecord = true
+		quietLog(t)
+		out := make([]uint16, 16)
+		allocs := testing.AllocsPerRun(100, func() {
+			if err := v.ReadRegU16BlockInto(0x10, out, binary.BigEndian); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("combined %v: %v allocs per call, want 0", combined, allocs)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package i2c
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Bus represents a connection to I2C bus, shared by several
+// devices: operations switch slave address as needed,
+// serialized by single bus lock.
+type Bus struct {
+	mu  sync.Mutex
+	dev *I2C
+	// true, if slave address has been set
+	addrSet bool
+}
+
+// tokenBucket limit rate of transferred bytes: bucket refill
+// with rate tokens per second up to burst tokens, each byte
+// consume one token. Transfer exceeding available tokens
+// wait until bucket refill enough to pay the debt.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take consume n tokens, sleeping as needed.
+func (tb *tokenBucket) take(clock Clock, n int) {
+	now := clock.Now()
+	if !tb.last.IsZero() {
+		tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+	}
+	tb.last = now
+	tb.tokens -= float64(n)
+	if tb.tokens < 0 {
+		d := time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+		clock.Sleep(d)
+		tb.tokens = 0
+		tb.last = now.Add(d)
+	}
+}
+
+// DeviceRead describe read of N bytes starting from
+// register Reg of I2C-device at address Addr.
+type DeviceRead struct {
+	Addr uint8
+	Reg  byte
+	N    int
+}
+
+// DeviceResult contains data read by DeviceRead request.
+type DeviceResult struct {
+	Addr uint8
+	Reg  byte
+	Data []byte
+}
+
+// NewBus opens a connection to I2C bus.
+func NewBus(bus int) (*Bus, error) {
+	f, err := openDevice(busPath(bus))
+	if err != nil {
+		return nil, err
+	}
+	b := &Bus{dev: &I2C{rc: f, bus: bus, clock: systemClock{}}}
+	return b, nil
+}
+
+// GetBus return bus line number.
+func (b *Bus) GetBus() int {
+	return b.dev.bus
+}
+
+// Close I2C bus connection.
+func (b *Bus) Close() error {
+	return b.dev.Close()
+}
+
+// SetClock replace clock used by bus connection for delays,
+// including rate limit pacing. Nil value restore system clock.
+func (b *Bus) SetClock(clock Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dev.SetClock(clock)
+}
+
+// SetRateLimit cap aggregate bus bandwidth used by all operations
+// of this bus connection to bytesPerSec bytes per second (counting
+// register address and data bytes), leaving headroom for other bus
+// users. Every transfer of bus connection is paced with token bucket,
+// allowing bursts of up to one second worth of bytes. Zero or negative
+// value remove limit.
+func (b *Bus) SetRateLimit(bytesPerSec int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bytesPerSec <= 0 {
+		b.dev.limit = nil
+		return
+	}
+	rate := float64(bytesPerSec)
+	b.dev.limit = &tokenBucket{rate: rate, burst: rate, tokens: rate}
+}
+
+// selectAddr switch bus connection to slave address addr.
+func (b *Bus) selectAddr(addr uint8) error {
+	if b.addrSet && b.dev.addr == addr {
+		return nil
+	}
+	if err := b.dev.setAddr(addr); err != nil {
+		return err
+	}
+	b.addrSet = true
+	return nil
+}
+
+// ReadMany execute reads from several devices in sequence under single
+// bus lock (switching slave address as needed), returning results
+// in request order. So readings from several devices are gathered
+// atomically relative to other bus users. Stops at first failure.
+// SMBus (System Management Bus) protocol over I2C.
+func (b *Bus) ReadMany(requests []DeviceRead) ([]DeviceResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	results := make([]DeviceResult, 0, len(requests))
+	for _, req := range requests {
+		if err := b.selectAddr(req.Addr); err != nil {
+			return results, fmt.Errorf("address 0x%0X: %w", req.Addr, err)
+		}
+		data, _, err := b.dev.readRegBytes(req.Reg, req.N)
+		if err != nil {
+			return results, fmt.Errorf("address 0x%0X, reg 0x%0X: %w", req.Addr, req.Reg, err)
+		}
+		results = append(results, DeviceResult{Addr: req.Addr, Reg: req.Reg, Data: data})
+	}
+	return results, nil
+}
@@ -0,0 +1,111 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBusReadMany(t *testing.T) {
+	a := newFakeAdapter()
+	copy(a.chip(0x40).regs[0x00:], []byte{0x11, 0x12})
+	copy(a.chip(0x41).regs[0x00:], []byte{0x21, 0x22})
+	copy(a.chip(0x76).regs[0xD0:], []byte{0x60})
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	b, err := NewBus(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	requests := []DeviceRead{
+		{Addr: 0x40, Reg: 0x00, N: 2},
+		{Addr: 0x40, Reg: 0x01, N: 1},
+		{Addr: 0x76, Reg: 0xD0, N: 1},
+		{Addr: 0x41, Reg: 0x00, N: 2},
+	}
+	results, err := b.ReadMany(requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x11, 0x12}, {0x12}, {0x60}, {0x21, 0x22}}
+	if len(results) != len(want) {
+		t.Fatalf("%d results, want %d", len(results), len(want))
+	}
+	for i, res := range results {
+		if res.Addr != requests[i].Addr || res.Reg != requests[i].Reg ||
+			!bytes.Equal(res.Data, want[i]) {
+			t.Errorf("result %d: %+v, want data [% X]", i, res, want[i])
+		}
+	}
+	// Slave address switched only when it changes.
+	var addrs []uintptr
+	for _, op := range a.operations("ioctl") {
+		if op.cmd == I2C_SLAVE {
+			addrs = append(addrs, op.arg)
+		}
+	}
+	if len(addrs) != 3 || addrs[0] != 0x40 || addrs[1] != 0x76 || addrs[2] != 0x41 {
+		t.Errorf("slave addresses set %X, want [40 76 41]", addrs)
+	}
+
+	// Missing device stops batch with partial results.
+	results, err = b.ReadMany([]DeviceRead{{Addr: 0x41, Reg: 0x00, N: 1}, {Addr: 0x42, Reg: 0x00, N: 1}})
+	if err == nil || len(results) != 1 {
+		t.Errorf("got %d results, %v, want 1 result and error", len(results), err)
+	}
+	// Cause of failure is kept.
+	if !errors.Is(err, ErrDeviceNotPresent) {
+		t.Errorf("error %v doesn't match ErrDeviceNotPresent", err)
+	}
+}
+
+func TestBusRateLimit(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x40)
+	a.chip(0x41)
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	b, err := NewBus(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	clock := newFakeClock()
+	b.SetClock(clock)
+	b.SetRateLimit(100)
+
+	// Each read costs 50 bytes (register address and 49 data bytes):
+	// the first 2 fit initial burst, the rest are paced at 100 bytes/s.
+	start := clock.Now()
+	for i := 0; i < 5; i++ {
+		addr := uint8(0x40 + i%2)
+		if _, err := b.ReadMany([]DeviceRead{{Addr: addr, Reg: 0x00, N: 49}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := clock.Now().Sub(start); d != 1500*time.Millisecond {
+		t.Errorf("250 bytes transferred in %v, want 1.5s", d)
+	}
+	// Each transfer is paced: register address write, then data read.
+	slept := clock.Slept()
+	want := []time.Duration{10, 490, 10, 490, 10, 490}
+	if len(slept) != len(want) {
+		t.Fatalf("pacing delays %v, want %v ms", slept, want)
+	}
+	for i := range want {
+		if slept[i] != want[i]*time.Millisecond {
+			t.Errorf("pacing delays %v, want %v ms", slept, want)
+			break
+		}
+	}
+
+	// Limit removed.
+	b.SetRateLimit(0)
+	if _, err := b.ReadMany([]DeviceRead{{Addr: 0x40, Reg: 0x00, N: 200}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(clock.Slept()) != len(want) {
+		t.Error("read paced after limit removed")
+	}
+}
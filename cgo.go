@@ -1,12 +1,80 @@
+//go:build linux && cgo
 // +build linux,cgo
 
 package i2c
 
+// #include <linux/i2c.h>
 // #include <linux/i2c-dev.h>
 import "C"
 
-// Get I2C_SLAVE constant value from
+// Get I2C ioctl constant values from
 // Linux OS I2C declaration file.
 const (
-	I2C_SLAVE = C.I2C_SLAVE
+	I2C_RETRIES     = C.I2C_RETRIES
+	I2C_SLAVE       = C.I2C_SLAVE
+	I2C_SLAVE_FORCE = C.I2C_SLAVE_FORCE
+	I2C_TENBIT      = C.I2C_TENBIT
+	I2C_FUNCS       = C.I2C_FUNCS
+	I2C_RDWR        = C.I2C_RDWR
+	I2C_TIMEOUT     = C.I2C_TIMEOUT
+	I2C_PEC         = C.I2C_PEC
+	I2C_SMBUS       = C.I2C_SMBUS
+)
+
+// Get maximum number of messages in single I2C_RDWR call.
+const I2C_RDWR_IOCTL_MAX_MSGS = C.I2C_RDWR_IOCTL_MAX_MSGS
+
+// Get I2C message flags, used in
+// I2C_RDWR combined transactions.
+const (
+	I2C_M_RD           = C.I2C_M_RD
+	I2C_M_TEN          = C.I2C_M_TEN
+	I2C_M_RECV_LEN     = C.I2C_M_RECV_LEN
+	I2C_M_NO_RD_ACK    = C.I2C_M_NO_RD_ACK
+	I2C_M_IGNORE_NAK   = C.I2C_M_IGNORE_NAK
+	I2C_M_REV_DIR_ADDR = C.I2C_M_REV_DIR_ADDR
+	I2C_M_NOSTART      = C.I2C_M_NOSTART
+	I2C_M_STOP         = C.I2C_M_STOP
+)
+
+// Get I2C adapter functionality flags,
+// returned by I2C_FUNCS ioctl.
+const (
+	I2C_FUNC_I2C                    = C.I2C_FUNC_I2C
+	I2C_FUNC_10BIT_ADDR             = C.I2C_FUNC_10BIT_ADDR
+	I2C_FUNC_PROTOCOL_MANGLING      = C.I2C_FUNC_PROTOCOL_MANGLING
+	I2C_FUNC_SMBUS_PEC              = C.I2C_FUNC_SMBUS_PEC
+	I2C_FUNC_NOSTART                = C.I2C_FUNC_NOSTART
+	I2C_FUNC_SLAVE                  = C.I2C_FUNC_SLAVE
+	I2C_FUNC_SMBUS_BLOCK_PROC_CALL  = C.I2C_FUNC_SMBUS_BLOCK_PROC_CALL
+	I2C_FUNC_SMBUS_QUICK            = C.I2C_FUNC_SMBUS_QUICK
+	I2C_FUNC_SMBUS_READ_BYTE        = C.I2C_FUNC_SMBUS_READ_BYTE
+	I2C_FUNC_SMBUS_WRITE_BYTE       = C.I2C_FUNC_SMBUS_WRITE_BYTE
+	I2C_FUNC_SMBUS_READ_BYTE_DATA   = C.I2C_FUNC_SMBUS_READ_BYTE_DATA
+	I2C_FUNC_SMBUS_WRITE_BYTE_DATA  = C.I2C_FUNC_SMBUS_WRITE_BYTE_DATA
+	I2C_FUNC_SMBUS_READ_WORD_DATA   = C.I2C_FUNC_SMBUS_READ_WORD_DATA
+	I2C_FUNC_SMBUS_WRITE_WORD_DATA  = C.I2C_FUNC_SMBUS_WRITE_WORD_DATA
+	I2C_FUNC_SMBUS_PROC_CALL        = C.I2C_FUNC_SMBUS_PROC_CALL
+	I2C_FUNC_SMBUS_READ_BLOCK_DATA  = C.I2C_FUNC_SMBUS_READ_BLOCK_DATA
+	I2C_FUNC_SMBUS_WRITE_BLOCK_DATA = C.I2C_FUNC_SMBUS_WRITE_BLOCK_DATA
+	I2C_FUNC_SMBUS_READ_I2C_BLOCK   = C.I2C_FUNC_SMBUS_READ_I2C_BLOCK
+	I2C_FUNC_SMBUS_WRITE_I2C_BLOCK  = C.I2C_FUNC_SMBUS_WRITE_I2C_BLOCK
+	I2C_FUNC_SMBUS_HOST_NOTIFY      = C.I2C_FUNC_SMBUS_HOST_NOTIFY
+)
+
+// Get SMBus transfer directions and transaction
+// types, used in I2C_SMBUS ioctl.
+const (
+	I2C_SMBUS_READ  = C.I2C_SMBUS_READ
+	I2C_SMBUS_WRITE = C.I2C_SMBUS_WRITE
+
+	I2C_SMBUS_QUICK            = C.I2C_SMBUS_QUICK
+	I2C_SMBUS_BYTE             = C.I2C_SMBUS_BYTE
+	I2C_SMBUS_BYTE_DATA        = C.I2C_SMBUS_BYTE_DATA
+	I2C_SMBUS_WORD_DATA        = C.I2C_SMBUS_WORD_DATA
+	I2C_SMBUS_PROC_CALL        = C.I2C_SMBUS_PROC_CALL
+	I2C_SMBUS_BLOCK_DATA       = C.I2C_SMBUS_BLOCK_DATA
+	I2C_SMBUS_I2C_BLOCK_BROKEN = C.I2C_SMBUS_I2C_BLOCK_BROKEN
+	I2C_SMBUS_BLOCK_PROC_CALL  = C.I2C_SMBUS_BLOCK_PROC_CALL
+	I2C_SMBUS_I2C_BLOCK_DATA   = C.I2C_SMBUS_I2C_BLOCK_DATA
 )
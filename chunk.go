@@ -0,0 +1,209 @@
+package i2c
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Transfer size limits used to recommend chunk size. Chunk is
+// a whole write message size, including register address bytes.
+const (
+	// Safe size for adapters, supporting plain I2C transfers
+	// (kernel i2c-dev itself allows up to 8192 bytes, but many
+	// USB bridges can't do that much in one go).
+	i2cChunk = 256
+	// SMBus I2C block write: command byte and maximum block.
+	smbusChunk = 1 + i2cSmbusBlockMax
+	// SMBus word write: command byte and 2 data bytes.
+	smbusWordChunk = 3
+	// SMBus byte write: command byte and data byte.
+	smbusByteChunk = 2
+	// Conservative default, when limits can't be determined.
+	defaultChunk = i2cSmbusBlockMax
+)
+
+// chunkForFuncs map adapter functionality mask to safe write chunk size.
+func chunkForFuncs(funcs uint32) int {
+	switch {
+	case funcs&I2C_FUNC_I2C != 0:
+		return i2cChunk
+	case funcs&I2C_FUNC_SMBUS_WRITE_I2C_BLOCK != 0:
+		return smbusChunk
+	case funcs&I2C_FUNC_SMBUS_WRITE_WORD_DATA != 0:
+		return smbusWordChunk
+	case funcs&I2C_FUNC_SMBUS_WRITE_BYTE_DATA != 0:
+		return smbusByteChunk
+	}
+	return defaultChunk
+}
+
+// RecommendedChunk query functionality of I2C adapter on bus
+// and returns safe chunk size (whole message, including register
+// address) for WriteChunked and similar writes: adapters, capable
+// of plain I2C transfers, allow large writes, while SMBus-only ones
+// are capped at 33 bytes (command and 32 bytes SMBus block) or less.
+// When functionality can't be determined, conservative default
+// (32 bytes) is returned.
+func RecommendedChunk(bus int) (int, error) {
+	f, err := openDevice(busPath(bus))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	funcs, err := queryFuncs(f)
+	if err != nil {
+		lg.Debugf("Can't query bus %d functionality: %v", bus, err)
+		return defaultChunk, nil
+	}
+	return chunkForFuncs(funcs), nil
+}
+
+// ackPollInterval is a pause between probes, while waiting
+// for device acknowledge after chunk write.
+const ackPollInterval = time.Millisecond
+
+// WithChunkAckPolling make chunked writes (see WriteChunked) poll
+// device with Probe before every next chunk (after delay, if any),
+// until it acknowledge its address, as EEPROMs do, when internal write
+// cycle is complete. Write fails with ErrTimeout, if device doesn't
+// acknowledge within timeout. Lets write proceed as soon as device
+// is ready, instead of waiting for worst case write cycle time.
+func WithChunkAckPolling(timeout time.Duration) Option {
+	return func(v *I2C) error {
+		if timeout <= 0 {
+			return fmt.Errorf("ACK polling timeout %v must be positive", timeout)
+		}
+		v.ackPoll = timeout
+		return nil
+	}
+}
+
+// waitAck poll device with probe, until it acknowledge its address,
+// failing with ErrTimeout, if it doesn't happen within ACK polling
+// timeout. Connection lock must be held.
+func (v *I2C) waitAck(ctx context.Context) error {
+	deadline := v.clock.Now().Add(v.ackPoll)
+	for {
+		ok, err := v.probe()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if !v.clock.Now().Before(deadline) {
+			return fmt.Errorf("%w: device at address 0x%0X not acknowledged within %v",
+				ErrTimeout, v.GetAddr16(), v.ackPoll)
+		}
+		if err := sleepContext(ctx, v.clock, ackPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// writeChunks writes data to I2C-device in messages of chunk bytes at
+// most, each one starting with address, which addr encode for offset
+// of chunk first byte. Pauses delay between chunks and wait for device
+// acknowledge, if enabled with WithChunkAckPolling. Stops with
+// ctx.Err() between chunks. Settle delays apply to 8-bit register
+// addresses only. Connection lock must be held.
+func (v *I2C) writeChunks(ctx context.Context, data []byte, chunk int,
+	addr func(offset int) []byte, delay time.Duration) error {
+
+	n := chunk - len(addr(0))
+	if n < 1 {
+		return fmt.Errorf("chunk size %d too small to carry %d address bytes and data",
+			chunk, len(addr(0)))
+	}
+	for offset := 0; offset < len(data); offset += n {
+		if offset > 0 {
+			if err := sleepContext(ctx, v.clock, delay); err != nil {
+				return err
+			}
+			if v.ackPoll > 0 {
+				if err := v.waitAck(ctx); err != nil {
+					return err
+				}
+			}
+		}
+		end := offset + n
+		if end > len(data) {
+			end = len(data)
+		}
+		a := addr(offset)
+		write := v.writeBytes
+		if len(a) > 1 {
+			write = v.writeBytes16
+		}
+		buf := append(a, data[offset:end]...)
+		if _, err := write(nextTx(), buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteChunked writes large data to I2C-device starting from reg address,
+// splitting it to write messages of chunk bytes at most (including
+// register address byte), each one prefixed with register address
+// advanced by chunk offset, and pausing delay between chunks (to let
+// device complete internal write cycle). See WithChunkAckPolling to
+// wait for device readiness between chunks as well.
+// Prevents exceeding adapter transfer limits with single huge write.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteChunked(reg byte, data []byte, chunk int, delay time.Duration) error {
+	return v.WriteChunkedContext(context.Background(), reg, data, chunk, delay)
+}
+
+// WriteChunkedContext is a variant of WriteChunked, which stops with
+// ctx.Err() between chunks, when ctx is canceled or its deadline passes.
+func (v *I2C) WriteChunkedContext(ctx context.Context, reg byte, data []byte, chunk int,
+	delay time.Duration) error {
+	if int(reg)+len(data) > 0x100 {
+		return fmt.Errorf("%d bytes starting from reg 0x%0X exceed register space",
+			len(data), reg)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	addr := func(offset int) []byte {
+		return []byte{reg + byte(offset)}
+	}
+	if err := v.writeChunks(ctx, data, chunk, addr, delay); err != nil {
+		return err
+	}
+	v.log.Debugf("Write %d bytes in chunks of %d to reg 0x%0X", len(data), chunk, reg)
+	return nil
+}
+
+// WriteRegBytesChunked writes data to I2C-device with 16-bit register
+// (memory) addresses, like EEPROMs, starting from reg address: data is
+// split to write messages of chunk bytes at most (including 2 address
+// bytes), each one prefixed with address advanced by chunk offset (byte
+// order set by WithRegAddrOrder), pausing delay between chunks to let
+// device complete internal write cycle. See WithChunkAckPolling to wait
+// for device readiness between chunks as well. Non-positive chunk select
+// default of 32 bytes, which fits SMBus layer and most USB bridges.
+// Mind EEPROM page size: data part of chunk must not cross page
+// boundary, otherwise address wraps within page.
+func (v *I2C) WriteRegBytesChunked(reg uint16, data []byte, chunk int,
+	delay time.Duration) error {
+
+	if chunk <= 0 {
+		chunk = defaultChunk
+	}
+	if int(reg)+len(data) > 0x10000 {
+		return fmt.Errorf("%d bytes starting from reg 0x%04X exceed address space",
+			len(data), reg)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	addr := func(offset int) []byte {
+		return v.regAddr16(reg + uint16(offset))
+	}
+	if err := v.writeChunks(context.Background(), data, chunk, addr, delay); err != nil {
+		return err
+	}
+	v.log.Debugf("Write %d bytes in chunks of %d to reg 0x%04X", len(data), chunk, reg)
+	return nil
+}
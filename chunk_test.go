@@ -0,0 +1,189 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteChunked(t *testing.T) {
+	v, a, clock := newFake(t, 0x50)
+	data := []byte{1, 2, 3, 4, 5, 6, 7}
+	if err := v.WriteChunked(0x10, data, 4, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// 4 bytes per message: register address and 3 data bytes.
+	want := [][]byte{{0x10, 1, 2, 3}, {0x13, 4, 5, 6}, {0x16, 7}}
+	w := a.writes()
+	if len(w) != len(want) {
+		t.Fatalf("writes %X, want %X", w, want)
+	}
+	for i := range want {
+		if !bytes.Equal(w[i], want[i]) {
+			t.Errorf("chunk %d: [% X], want [% X]", i, w[i], want[i])
+		}
+	}
+	// Delay between chunks, not after the last one.
+	ops := a.operations("write")
+	for i := 1; i < len(ops); i++ {
+		if d := ops[i].at.Sub(ops[i-1].at); d != 5*time.Millisecond {
+			t.Errorf("delay before chunk %d: %v, want 5ms", i, d)
+		}
+	}
+	if slept := clock.Slept(); len(slept) != 2 {
+		t.Errorf("sleeps %v, want 2", slept)
+	}
+	if !bytes.Equal(a.chip(0x50).regs[0x10:0x17], data) {
+		t.Errorf("device memory [% X]", a.chip(0x50).regs[0x10:0x17])
+	}
+
+	if err := v.WriteChunked(0x10, data, 1, 0); err == nil {
+		t.Error("chunk without room for data accepted")
+	}
+	if err := v.WriteChunked(0xFE, data, 4, 0); err == nil {
+		t.Error("write beyond register space accepted")
+	}
+}
+
+func TestWriteChunkedAckPolling(t *testing.T) {
+	v, a, _ := newFake(t, 0x50, WithChunkAckPolling(10*time.Millisecond))
+	c := a.chip(0x50)
+	// Device busy with write cycle NAK two probes after each chunk.
+	writes := 0
+	c.onWrite = func(c *fakeChip) {
+		writes++
+		c.nak = 2
+	}
+	data := []byte{1, 2, 3, 4}
+	if err := v.WriteChunked(0x00, data, 3, 0); err != nil {
+		t.Fatal(err)
+	}
+	if writes != 2 {
+		t.Fatalf("%d chunks written, want 2", writes)
+	}
+	probes := 0
+	for _, op := range a.operations("smbus") {
+		if op.cmd == I2C_SMBUS_QUICK {
+			probes++
+		}
+	}
+	if probes != 3 {
+		t.Errorf("%d probes between chunks, want 3", probes)
+	}
+
+	// Device never acknowledge.
+	c.nak = 0
+	c.onWrite = func(c *fakeChip) { c.nak = 1000 }
+	if err := v.WriteChunked(0x00, data, 3, 0); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestChunkForFuncs(t *testing.T) {
+	tests := []struct {
+		funcs uint32
+		want  int
+	}{
+		{I2C_FUNC_I2C | I2C_FUNC_SMBUS_WRITE_I2C_BLOCK, 256},
+		{I2C_FUNC_SMBUS_WRITE_I2C_BLOCK | I2C_FUNC_SMBUS_WRITE_BYTE_DATA, 33},
+		{I2C_FUNC_SMBUS_WRITE_WORD_DATA | I2C_FUNC_SMBUS_WRITE_BYTE_DATA, 3},
+		{I2C_FUNC_SMBUS_WRITE_BYTE_DATA, 2},
+		{I2C_FUNC_SMBUS_QUICK, 32},
+		{0, 32},
+	}
+	for _, test := range tests {
+		if got := chunkForFuncs(test.funcs); got != test.want {
+			t.Errorf("funcs 0x%08X: chunk %d, want %d", test.funcs, got, test.want)
+		}
+	}
+}
+
+func TestRecommendedChunk(t *testing.T) {
+	a := newFakeAdapter()
+	a.funcs = I2C_FUNC_SMBUS_WRITE_I2C_BLOCK
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	if chunk, err := RecommendedChunk(1); err != nil || chunk != 33 {
+		t.Errorf("RecommendedChunk = %d, %v, want 33", chunk, err)
+	}
+	if _, err := RecommendedChunk(2); err == nil {
+		t.Error("missing bus accepted")
+	}
+}
+
+func TestWriteRegBytesChunked(t *testing.T) {
+	v, a, clock := newFake(t, 0x50)
+	c := a.chip(0x50)
+	c.addrLen = 2
+	data := make([]byte, 70)
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	if err := v.WriteRegBytesChunked(0x01F0, data, 0, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// Default chunk of 32 bytes: 2 address bytes and 30 data bytes,
+	// address crossing 0x0200 carried to high byte.
+	w := a.writes()
+	wantAddr := [][]byte{{0x01, 0xF0}, {0x02, 0x0E}, {0x02, 0x2C}}
+	wantLen := []int{32, 32, 12}
+	if len(w) != len(wantAddr) {
+		t.Fatalf("%d chunks written, want %d", len(w), len(wantAddr))
+	}
+	offset := 0
+	for i := range w {
+		if len(w[i]) != wantLen[i] || !bytes.Equal(w[i][:2], wantAddr[i]) {
+			t.Errorf("chunk %d: address [% X], %d bytes, want [% X], %d bytes",
+				i, w[i][:2], len(w[i]), wantAddr[i], wantLen[i])
+			continue
+		}
+		if !bytes.Equal(w[i][2:], data[offset:offset+wantLen[i]-2]) {
+			t.Errorf("chunk %d data [% X]", i, w[i][2:])
+		}
+		offset += wantLen[i] - 2
+	}
+	if !bytes.Equal(c.regs[0x01F0:0x01F0+len(data)], data) {
+		t.Errorf("device memory [% X]", c.regs[0x01F0:0x01F0+len(data)])
+	}
+	if slept := clock.Slept(); len(slept) != 2 || slept[0] != 5*time.Millisecond {
+		t.Errorf("delays %v, want 5ms between chunks", slept)
+	}
+
+	// Chunk boundaries follow explicit chunk size, address byte
+	// order follows WithRegAddrOrder.
+	a.reset()
+	if err := v.WriteRegBytesChunked(0x0100, data[:5], 4, 0); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x01, 0x00, 1, 2}, {0x01, 0x02, 3, 4}, {0x01, 0x04, 5}}
+	if w := a.writes(); len(w) != len(want) || !bytes.Equal(w[0], want[0]) ||
+		!bytes.Equal(w[1], want[1]) || !bytes.Equal(w[2], want[2]) {
+		t.Errorf("writes %X, want %X", w, want)
+	}
+
+	if err := v.WriteRegBytesChunked(0x0000, data, 2, 0); err == nil {
+		t.Error("chunk without room for data accepted")
+	}
+	if err := v.WriteRegBytesChunked(0xFFF0, data[:17], 0, 0); err == nil {
+		t.Error("write beyond address space accepted")
+	}
+}
+
+func TestWriteRegBytesChunkedAckPolling(t *testing.T) {
+	v, a, _ := newFake(t, 0x50, WithChunkAckPolling(10*time.Millisecond))
+	c := a.chip(0x50)
+	c.addrLen = 2
+	c.onWrite = func(c *fakeChip) {
+		c.nak = 1
+	}
+	if err := v.WriteRegBytesChunked(0x0010, []byte{1, 2, 3, 4}, 4, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Probes between chunks only: one NAKed, then acknowledged.
+	if probes := a.operations("smbus"); len(probes) != 2 {
+		t.Errorf("%d probes, want 2", len(probes))
+	}
+	if !bytes.Equal(c.regs[0x10:0x14], []byte{1, 2, 3, 4}) {
+		t.Errorf("device memory [% X]", c.regs[0x10:0x14])
+	}
+}
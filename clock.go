@@ -0,0 +1,88 @@
+package i2c
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides time functions used by the package
+// for delays, timeouts and time measurements.
+// Default implementation use system time, but it can be
+// replaced via SetClock, to get deterministic behavior in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock implements Clock with standard time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// sleepContext pause for d according to clock,
+// returning ctx.Err() early, if ctx is done.
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
+
+// SetClock replace clock used by I2C-connection for delays
+// and timeouts. Nil value restore system clock.
+func (v *I2C) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	v.clock = clock
+}
+
+// pace enforce minimum interval between operations.
+type pace struct {
+	interval time.Duration
+	mu       sync.Mutex
+	last     time.Time
+}
+
+// wait sleep until interval elapse since last operation.
+func (p *pace) wait(clock Clock) {
+	if p.interval <= 0 {
+		return
+	}
+	p.mu.Lock()
+	last := p.last
+	p.mu.Unlock()
+	if last.IsZero() {
+		return
+	}
+	if d := p.interval - clock.Now().Sub(last); d > 0 {
+		clock.Sleep(d)
+	}
+}
+
+// mark remember time of operation.
+func (p *pace) mark(clock Clock) {
+	if p.interval <= 0 {
+		return
+	}
+	p.mu.Lock()
+	p.last = clock.Now()
+	p.mu.Unlock()
+}
@@ -0,0 +1,117 @@
+package i2c
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// sortedRegs return register addresses of m in ascending order.
+func sortedRegs(m map[byte]byte) []byte {
+	regs := make([]byte, 0, len(m))
+	for reg := range m {
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i] < regs[j] })
+	return regs
+}
+
+// ApplyConfig writes each register value from config to I2C-device
+// (in ascending register order), then reads all of them back and
+// returns map of mismatches: register address to value actually read.
+// Empty map means whole configuration verified.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ApplyConfig(config map[byte]byte) (map[byte]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	regs := sortedRegs(config)
+	for _, reg := range regs {
+		if err := v.writeRegU8(reg, config[reg]); err != nil {
+			return nil, err
+		}
+	}
+	mismatches := make(map[byte]byte)
+	for _, reg := range regs {
+		b, err := v.readRegU8(reg)
+		if err != nil {
+			return nil, err
+		}
+		if b != config[reg] {
+			v.log.Debugf("Reg 0x%0X mismatch: wrote 0x%0X, read 0x%0X", reg, config[reg], b)
+			mismatches[reg] = b
+		}
+	}
+	return mismatches, nil
+}
+
+// VerifyDefaults reads each register listed in expected from I2C-device
+// (in ascending register order) and returns map of mismatches: register
+// address to pair of expected and actual values. Empty map means chip
+// is in expected (datasheet power-on) default state.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) VerifyDefaults(expected map[byte]byte) (map[byte][2]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	mismatches := make(map[byte][2]byte)
+	for _, reg := range sortedRegs(expected) {
+		b, err := v.readRegU8(reg)
+		if err != nil {
+			return nil, err
+		}
+		if b != expected[reg] {
+			v.log.Debugf("Reg 0x%0X differ from default: expected 0x%0X, read 0x%0X",
+				reg, expected[reg], b)
+			mismatches[reg] = [2]byte{expected[reg], b}
+		}
+	}
+	return mismatches, nil
+}
+
+// SetAllowedValues restrict values, which can be written to register reg
+// with WriteRegU8, to allowed set: other values are rejected with
+// ErrInvalidValue before hitting the bus. It catches driver bugs,
+// which would put device to undefined mode. Empty allowed
+// removes restriction.
+func (v *I2C) SetAllowedValues(reg byte, allowed []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(allowed) == 0 {
+		delete(v.allowed, reg)
+		return
+	}
+	if v.allowed == nil {
+		v.allowed = make(map[byte][]byte)
+	}
+	v.allowed[reg] = append([]byte(nil), allowed...)
+}
+
+// checkAllowed verify value against set of values
+// allowed for register reg, if any.
+func (v *I2C) checkAllowed(reg byte, value byte) error {
+	allowed, ok := v.allowed[reg]
+	if !ok {
+		return nil
+	}
+	for _, b := range allowed {
+		if b == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 0x%0X not allowed for reg 0x%0X", ErrInvalidValue, value, reg)
+}
+
+// SetWriteSettle make every write to register reg followed by delay d,
+// before connection lock is released, so next operation can't start
+// until register settles (PLL lock, for instance). Zero d removes delay.
+func (v *I2C) SetWriteSettle(reg byte, d time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if d <= 0 {
+		delete(v.settle, reg)
+		return
+	}
+	if v.settle == nil {
+		v.settle = make(map[byte]time.Duration)
+	}
+	v.settle[reg] = d
+}
@@ -0,0 +1,126 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyConfig(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	// Device ignores writes to reg 0x03.
+	a.chip(0x40).onRead = func(c *fakeChip) { c.regs[0x03] = 0x00 }
+	config := map[byte]byte{0x05: 0x55, 0x01: 0x11, 0x03: 0x33}
+	mismatches, err := v.ApplyConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 1 || mismatches[0x03] != 0x00 {
+		t.Errorf("mismatches %v, want map[3:0]", mismatches)
+	}
+	// Writes go in ascending register order.
+	var order []byte
+	for _, w := range a.writes() {
+		if len(w) == 2 {
+			order = append(order, w[0])
+		}
+	}
+	if !bytes.Equal(order, []byte{0x01, 0x03, 0x05}) {
+		t.Errorf("registers written in order [% X]", order)
+	}
+}
+
+func TestSetAllowedValues(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	v.SetAllowedValues(0x0A, []byte{0x00, 0x03})
+	if err := v.WriteRegU8(0x0A, 0x03); err != nil {
+		t.Errorf("allowed value rejected: %v", err)
+	}
+	if err := v.WriteRegU8(0x0A, 0x02); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected ErrInvalidValue, got %v", err)
+	}
+	if err := v.WriteRegU8(0x0B, 0x02); err != nil {
+		t.Errorf("unrestricted register rejected: %v", err)
+	}
+	want := [][]byte{{0x0A, 0x03}, {0x0B, 0x02}}
+	if w := a.writes(); !reflect.DeepEqual(w, want) {
+		t.Errorf("writes %X, want %X", w, want)
+	}
+	v.SetAllowedValues(0x0A, nil)
+	if err := v.WriteRegU8(0x0A, 0x02); err != nil {
+		t.Errorf("restriction not removed: %v", err)
+	}
+}
+
+func TestSetWriteSettle(t *testing.T) {
+	v, _, clock := newFake(t, 0x40)
+	v.SetWriteSettle(0x0E, 2*time.Millisecond)
+	if err := v.WriteRegU8(0x0D, 0x01); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 0 {
+		t.Errorf("delay %v after write to other register", slept)
+	}
+	if err := v.WriteRegU8(0x0E, 0x01); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 2*time.Millisecond {
+		t.Errorf("delays %v after write to settle register, want [2ms]", slept)
+	}
+	// Register reads aren't delayed.
+	if _, err := v.ReadRegU8(0x0E); err != nil {
+		t.Fatal(err)
+	}
+	v.SetWriteSettle(0x0E, 0)
+	if err := v.WriteRegU8(0x0E, 0x02); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 {
+		t.Errorf("delays %v, settle not removed", slept)
+	}
+}
+
+func TestSetWriteSettleAddr16(t *testing.T) {
+	v, _, clock := newFake(t, 0x50)
+	v.SetWriteSettle(0x0E, 2*time.Millisecond)
+	// High byte of 16-bit address isn't 8-bit register address.
+	if err := v.WriteRegBytes16(0x0E00, []byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.WriteRegBytesChunked(0x0E00, make([]byte, 40), 32, 0); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 0 {
+		t.Errorf("delays %v after writes with 16-bit address", slept)
+	}
+	// 8-bit chunked writes still settle.
+	if err := v.WriteChunked(0x0E, []byte{0x01}, 8, 0); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 2*time.Millisecond {
+		t.Errorf("delays %v after chunked write to settle register, want [2ms]", slept)
+	}
+}
+
+func TestVerifyDefaults(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	defaults := map[byte]byte{0x00: 0x39, 0x01: 0x00, 0x05: 0x80}
+	for reg, b := range defaults {
+		c.regs[reg] = b
+	}
+	mismatches, err := v.VerifyDefaults(defaults)
+	if err != nil || len(mismatches) != 0 {
+		t.Errorf("chip in default state: %X, %v", mismatches, err)
+	}
+	c.regs[0x05] = 0x81
+	mismatches, err = v.VerifyDefaults(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[byte][2]byte{0x05: {0x80, 0x81}}; !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("mismatches %X, want %X", mismatches, want)
+	}
+}
@@ -0,0 +1,93 @@
+package i2c
+
+import (
+	"context"
+	"time"
+)
+
+// timeoutUnit is a unit of I2C_TIMEOUT ioctl argument.
+const timeoutUnit = 10 * time.Millisecond
+
+// timeoutUnits convert duration to I2C_TIMEOUT units (10 ms),
+// rounding up, so timeout never get shorter than requested.
+// Non-positive duration gives zero.
+func timeoutUnits(d time.Duration) uintptr {
+	if d <= 0 {
+		return 0
+	}
+	return uintptr((d + timeoutUnit - 1) / timeoutUnit)
+}
+
+// SetTimeout set adapter timeout for transfers with I2C_TIMEOUT ioctl,
+// so driver aborts transfer with stuck device instead of hanging
+// forever; applies to plain ReadBytes and WriteBytes as well.
+// Timeout is a setting of adapter, shared by all its users,
+// so it is never changed implicitly (by context deadlines, for instance).
+// Kernel counts timeout in 10 ms units: d is rounded up to the whole
+// number of units, and durations below 10 ms (including zero and
+// negative ones) give the minimum timeout of 10 ms.
+func (v *I2C) SetTimeout(d time.Duration) error {
+	units := timeoutUnits(d)
+	if units == 0 {
+		units = 1
+	}
+	return devIoctl(v.rc, I2C_TIMEOUT, units)
+}
+
+// ioResult is a result of transfer done in background.
+type ioResult struct {
+	n   int
+	err error
+}
+
+// ReadBytesContext is a variant of ReadBytes, which honors ctx: it
+// returns ctx.Err(), when ctx is canceled or its deadline passes before
+// read complete.
+//
+// I2C-device file can't be interrupted, so read itself runs in background
+// goroutine, which keeps using file descriptor after cancellation, until
+// driver completes or aborts transfer (buf is not modified after return
+// though). Next transfer may be queued behind it in driver, so use
+// SetTimeout to bound hung transfers on driver level.
+func (v *I2C) ReadBytesContext(ctx context.Context, buf []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	tmp := make([]byte, len(buf))
+	done := make(chan ioResult, 1)
+	go func() {
+		n, err := v.ReadBytes(tmp)
+		done <- ioResult{n, err}
+	}()
+	select {
+	case r := <-done:
+		copy(buf, tmp[:r.n])
+		return r.n, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// WriteBytesContext is a variant of WriteBytes, which honors ctx: it
+// returns ctx.Err(), when ctx is canceled or its deadline passes before
+// write complete. Like ReadBytesContext, write runs in background
+// goroutine, which keeps using file descriptor after cancellation,
+// until driver completes or aborts transfer (see SetTimeout): canceled
+// write may still reach I2C-device.
+func (v *I2C) WriteBytesContext(ctx context.Context, buf []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	tmp := append([]byte(nil), buf...)
+	done := make(chan ioResult, 1)
+	go func() {
+		n, err := v.WriteBytes(tmp)
+		done <- ioResult{n, err}
+	}()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package i2c
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// newPipe create pipe, closed at test end. Pipe ends satisfy device
+// interface, so they can serve as I2C-device file of connection.
+func newPipe(t *testing.T) (r, w *os.File) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+		w.Close()
+	})
+	return r, w
+}
+
+func TestReadBytesContext(t *testing.T) {
+	r, w := newPipe(t)
+	v := &I2C{rc: r, bus: 1, clock: systemClock{}}
+	quietLog(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf := make([]byte, 2)
+	if _, err := v.ReadBytesContext(ctx, buf); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// Nothing in pipe: read hangs until deadline.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := v.ReadBytesContext(ctx, buf); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("hung read aborted after %v", d)
+	}
+	// Release read left in background, which consumes
+	// the first 2 bytes, then read next ones.
+	if _, err := w.Write([]byte{0x01, 0x02, 0x03, 0x04}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	n, err := v.ReadBytesContext(context.Background(), buf)
+	if err != nil || n != 2 || !bytes.Equal(buf, []byte{0x03, 0x04}) {
+		t.Errorf("ReadBytesContext = %d, [% X], %v", n, buf, err)
+	}
+}
+
+func TestWriteBytesContext(t *testing.T) {
+	r, w := newPipe(t)
+	v := &I2C{rc: w, bus: 1, clock: systemClock{}}
+	quietLog(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.WriteBytesContext(ctx, []byte{0x01}); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n, err := v.WriteBytesContext(context.Background(), []byte{0x10, 0x20}); err != nil || n != 2 {
+		t.Fatalf("WriteBytesContext = %d, %v", n, err)
+	}
+	buf := make([]byte, 2)
+	if _, err := r.Read(buf); err != nil || !bytes.Equal(buf, []byte{0x10, 0x20}) {
+		t.Errorf("pipe received [% X], %v", buf, err)
+	}
+}
+
+func TestSetTimeout(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	tests := []struct {
+		d     time.Duration
+		units uintptr
+	}{
+		{-time.Second, 1},
+		{0, 1},
+		{time.Millisecond, 1},
+		{10 * time.Millisecond, 1},
+		{11 * time.Millisecond, 2},
+		{25 * time.Millisecond, 3},
+		{time.Second, 100},
+		{1500 * time.Millisecond, 150},
+	}
+	for _, test := range tests {
+		a.reset()
+		if err := v.SetTimeout(test.d); err != nil {
+			t.Fatal(err)
+		}
+		ops := a.operations("ioctl")
+		if len(ops) != 1 || ops[0].cmd != I2C_TIMEOUT || ops[0].arg != test.units {
+			t.Errorf("SetTimeout(%v): ioctls %+v, want I2C_TIMEOUT %d", test.d, ops, test.units)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package i2c
+
+import "fmt"
+
+// CRC8 calculate CRC-8 of data with polynomial x^8+x^2+x+1 (0x07)
+// and zero initial value, as defined for SMBus packet error checking.
+// To compute PEC, data must include address bytes: 7-bit device
+// address shifted left, with R/W bit set for read phase.
+func CRC8(data []byte) byte {
+	return crc8(0, data)
+}
+
+// crc8 calculate CRC-8 of data with polynomial x^8+x^2+x+1 (0x07)
+// and zero initial value, as defined for SMBus packet error checking.
+func crc8(crc byte, data []byte) byte {
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// checkFrameAddr verify, that checksum over frame addressing bytes
+// can be computed in software: it's defined for 7-bit address byte
+// only, while 10-bit address is sent as two-byte header.
+func (v *I2C) checkFrameAddr() error {
+	if v.tenBit {
+		return fmt.Errorf("frame checksum not supported for 10-bit address 0x%03X",
+			v.addr10)
+	}
+	return nil
+}
+
+// WriteRegFramed writes data to I2C-device register specified in reg,
+// appended with CRC-8 (polynomial 0x07) frame checksum.
+// Checksum covers whole frame including addressing bytes:
+// write address byte (7-bit device address shifted left, with R/W bit
+// cleared), register byte and all data bytes, what several sensor
+// protocols require. Unlike SMBus PEC, checksum is computed
+// in software and sent as ordinary data byte. Not supported
+// for connections in 10-bit addressing mode.
+func (v *I2C) WriteRegFramed(reg byte, data []byte) error {
+	// Address must not change between checksum and write.
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.checkFrameAddr(); err != nil {
+		return err
+	}
+	buf := make([]byte, 0, len(data)+2)
+	buf = append(buf, reg)
+	buf = append(buf, data...)
+	crc := crc8(crc8(0, []byte{v.addr << 1}), buf)
+	buf = append(buf, crc)
+	_, err := v.WriteBytes(buf)
+	if err != nil {
+		return err
+	}
+	v.log.Debugf("Write %d bytes framed with CRC 0x%02X to reg 0x%0X", len(data), crc, reg)
+	return nil
+}
+
+// BlockProcessCallPEC performs SMBus block write - block read process
+// call (as Smart Battery System commands use): writes command reg with
+// data block (up to 32 bytes) and, after repeated START, reads response
+// block, all in one combined I2C_RDWR transaction. Single PEC byte
+// received at the end is verified against checksum over whole frame:
+// write address, command, both count bytes with data blocks and read
+// address. Returns response block.
+// Adapter must support I2C_FUNC_I2C functionality and I2C_M_RECV_LEN
+// message flag (block length received from device).
+// Not supported for connections in 10-bit addressing mode.
+func (v *I2C) BlockProcessCallPEC(reg byte, data []byte) ([]byte, error) {
+	if len(data) > i2cSmbusBlockMax {
+		return nil, fmt.Errorf("block length %d exceed %d bytes", len(data), i2cSmbusBlockMax)
+	}
+	w := make([]byte, 0, len(data)+2)
+	w = append(w, reg, byte(len(data)))
+	w = append(w, data...)
+	// Kernel expect number of bytes to be read in addition
+	// to block data in r[0]: count byte and PEC byte.
+	r := make([]byte, i2cSmbusBlockMax+2)
+	r[0] = 2
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.checkFrameAddr(); err != nil {
+		return nil, err
+	}
+	tx := nextTx()
+	v.log.Debugf("[tx %d] Block process call with PEC to reg 0x%0X: [% X]", tx, reg, data)
+	t := NewTransaction().Write(w, 0).Read(r, I2C_M_RECV_LEN)
+	if err := v.Exec(t); err != nil {
+		v.setReadError(err)
+		return nil, err
+	}
+	count := int(r[0])
+	if count > i2cSmbusBlockMax {
+		return nil, fmt.Errorf("block length %d received from reg 0x%0X exceed %d bytes",
+			count, reg, i2cSmbusBlockMax)
+	}
+	crc := crc8(0, []byte{v.addr << 1})
+	crc = crc8(crc, w)
+	crc = crc8(crc, []byte{v.addr<<1 | 1})
+	crc = crc8(crc, r[:count+1])
+	if pec := r[count+1]; pec != crc {
+		return nil, fmt.Errorf("%w in block process call to reg 0x%0X: "+
+			"received 0x%02X, expected 0x%02X", ErrPECMismatch, reg, pec, crc)
+	}
+	v.log.Debugf("[tx %d] Block process call response: [% X]", tx, r[1:count+1])
+	return append([]byte(nil), r[1:count+1]...), nil
+}
+
+// ReadRegU8PEC reads byte from I2C-device register specified in reg,
+// followed by SMBus PEC byte, which is verified in software against
+// checksum over write address, reg, read address and data byte.
+// Checksum covers both phases joined by repeated START, so read is
+// always done as one combined I2C_RDWR transaction (whether or not
+// WithCombinedReads is set).
+// Returns error wrapping ErrPECMismatch on checksum mismatch.
+// Not supported for connections in 10-bit addressing mode.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU8PEC(reg byte) (byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.checkFrameAddr(); err != nil {
+		return 0, err
+	}
+	tx := nextTx()
+	buf := make([]byte, 2)
+	if _, err := v.readRegRDWR(tx, reg, buf); err != nil {
+		return 0, v.regError("read", reg, err)
+	}
+	crc := crc8(0, []byte{v.addr << 1, reg, v.addr<<1 | 1, buf[0]})
+	if buf[1] != crc {
+		return 0, fmt.Errorf("%w reading reg 0x%0X: received 0x%02X, expected 0x%02X",
+			ErrPECMismatch, reg, buf[1], crc)
+	}
+	v.log.Debugf("[tx %d] Read U8 %d with PEC from reg 0x%0X", tx, buf[0], reg)
+	return buf[0], nil
+}
+
+// WriteRegU8PEC writes byte to I2C-device register specified in reg,
+// followed by SMBus PEC byte computed in software over write address,
+// reg and value. Not supported for connections in 10-bit addressing mode.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegU8PEC(reg byte, value byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.checkFrameAddr(); err != nil {
+		return err
+	}
+	crc := crc8(0, []byte{v.addr << 1, reg, value})
+	if _, err := v.writeBytes(nextTx(), []byte{reg, value, crc}); err != nil {
+		return err
+	}
+	v.log.Debugf("Write U8 %d with PEC 0x%02X to reg 0x%0X", value, crc, reg)
+	return nil
+}
@@ -0,0 +1,165 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteRegFramed(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	if err := v.WriteRegFramed(0x10, []byte{0x01, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+	// CRC-8 over [0x80 0x10 0x01 0x02]: write address, reg and data.
+	want := []byte{0x10, 0x01, 0x02, 0x88}
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], want) {
+		t.Errorf("frame written %X, want [% X]", w, want)
+	}
+}
+
+func TestWriteRegFramedSetAddr(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x41)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			v.SetAddr(0x40 + uint8(i%2))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if err := v.WriteRegFramed(0x10, []byte{0x01}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+	// Each frame checksum covers address it was sent to.
+	for _, op := range a.operations("write") {
+		if crc := crc8(crc8(0, []byte{byte(op.addr) << 1}), op.data[:2]); crc != op.data[2] {
+			t.Errorf("frame [% X] sent to 0x%02X has wrong checksum", op.data, op.addr)
+		}
+	}
+}
+
+func TestWriteRegFramedTenBit(t *testing.T) {
+	v, a := newTenBitFake(t, 0x3A5)
+	if err := v.WriteRegFramed(0x10, []byte{0x01}); err == nil {
+		t.Error("frame checksum over 10-bit address accepted")
+	}
+	if w := a.writes(); len(w) != 0 {
+		t.Errorf("unexpected writes %X", w)
+	}
+}
+
+// newTenBitFake open connection in 10-bit addressing mode
+// to fake chip at addr.
+func newTenBitFake(t *testing.T, addr uint16) (*I2C, *fakeAdapter) {
+	a := newFakeAdapter()
+	a.chip(addr)
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	v, err := NewTenBit(addr, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { v.Close() })
+	a.reset()
+	return v, a
+}
+
+func TestBlockProcessCallPEC(t *testing.T) {
+	v, a, _ := newFake(t, 0x0B)
+	c := a.chip(0x0B)
+	// SBS ManufacturerBlockAccess (0x44) frame: write block [01 00],
+	// response block [01 00 11 22]. PEC over
+	// [16 44 02 01 00 17 04 01 00 11 22] is 0xC1.
+	// Fake chip stores write block from 0x44, so response
+	// is read from 0x47 on.
+	copy(c.regs[0x47:], []byte{0x04, 0x01, 0x00, 0x11, 0x22, 0xC1})
+	resp, err := v.BlockProcessCallPEC(0x44, []byte{0x01, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(resp, []byte{0x01, 0x00, 0x11, 0x22}) {
+		t.Errorf("response [% X]", resp)
+	}
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x44, 0x02, 0x01, 0x00}) {
+		t.Errorf("block written %X", w)
+	}
+
+	c.regs[0x47+5] = 0xC2
+	if _, err := v.BlockProcessCallPEC(0x44, []byte{0x01, 0x00}); !errors.Is(err, ErrPECMismatch) {
+		t.Errorf("expected ErrPECMismatch, got %v", err)
+	}
+}
+
+func TestBlockProcessCallPECTenBit(t *testing.T) {
+	v, a := newTenBitFake(t, 0x3A5)
+	if _, err := v.BlockProcessCallPEC(0x44, []byte{0x01}); err == nil {
+		t.Error("PEC over 10-bit address accepted")
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("unexpected operations %+v", ops)
+	}
+}
+
+func TestCRC8(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want byte
+	}{
+		// Check value of CRC-8/SMBUS.
+		{[]byte("123456789"), 0xF4},
+		{nil, 0x00},
+		{[]byte{0x01}, 0x07},
+		{[]byte{0xFF}, 0xF3},
+	}
+	for _, test := range tests {
+		if got := CRC8(test.data); got != test.want {
+			t.Errorf("CRC8(% X) = 0x%02X, want 0x%02X", test.data, got, test.want)
+		}
+	}
+}
+
+func TestRegU8PEC(t *testing.T) {
+	v, a, _ := newFake(t, 0x0B)
+	c := a.chip(0x0B)
+	// PEC over [16 08 17 5A]: write address, reg, read address, data.
+	copy(c.regs[0x08:], []byte{0x5A, 0xFF})
+	if b, err := v.ReadRegU8PEC(0x08); err != nil || b != 0x5A {
+		t.Errorf("ReadRegU8PEC = 0x%02X, %v", b, err)
+	}
+	// Repeated START between phases: one combined transaction,
+	// though combined reads aren't enabled for connection.
+	for _, op := range a.operations() {
+		if !op.rdwr {
+			t.Errorf("PEC read done with separate transfer: %+v", op)
+		}
+	}
+	c.regs[0x09] = 0xFE
+	if _, err := v.ReadRegU8PEC(0x08); !errors.Is(err, ErrPECMismatch) {
+		t.Errorf("expected ErrPECMismatch, got %v", err)
+	}
+
+	a.reset()
+	if err := v.WriteRegU8PEC(0x09, 0xA5); err != nil {
+		t.Fatal(err)
+	}
+	// PEC over [16 09 A5].
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x09, 0xA5, 0x10}) {
+		t.Errorf("writes %X, want [09 A5 10]", w)
+	}
+}
+
+func TestRegU8PECTenBit(t *testing.T) {
+	v, a := newTenBitFake(t, 0x3A5)
+	if _, err := v.ReadRegU8PEC(0x08); err == nil {
+		t.Error("PEC read over 10-bit address accepted")
+	}
+	if err := v.WriteRegU8PEC(0x08, 0x01); err == nil {
+		t.Error("PEC write over 10-bit address accepted")
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("unexpected operations %+v", ops)
+	}
+}
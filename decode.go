@@ -0,0 +1,206 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// isBigEndian detect byte order behavior of order.
+func isBigEndian(order binary.ByteOrder) bool {
+	return order.Uint16([]byte{0x00, 0x01}) == 0x0001
+}
+
+// decodeUint decode unsigned integer from buf of arbitrary
+// length (up to 8 bytes) with byte order specified in order.
+func decodeUint(buf []byte, order binary.ByteOrder) uint64 {
+	var u uint64
+	if isBigEndian(order) {
+		for _, b := range buf {
+			u = u<<8 | uint64(b)
+		}
+	} else {
+		for i := len(buf) - 1; i >= 0; i-- {
+			u = u<<8 | uint64(buf[i])
+		}
+	}
+	return u
+}
+
+// signExtend interpret lower bits of u as two's complement
+// signed value, extending sign from bit bits-1.
+func signExtend(u uint64, bits uint) int64 {
+	shift := 64 - bits
+	return int64(u<<shift) >> shift
+}
+
+// ReadRegSignedBits reads totalBytes from I2C-device starting from
+// address specified in reg, decode them with byte order specified
+// in order, mask result to lower validBits and sign-extend it
+// from bit validBits-1. Suitable for ADCs with 12-bit, 18-bit
+// and other resolutions, which don't fit byte-width helpers.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegSignedBits(reg byte, totalBytes int, validBits uint,
+	order binary.ByteOrder) (int32, error) {
+
+	if totalBytes < 1 || totalBytes > 4 {
+		return 0, fmt.Errorf("total bytes %d out of range 1..4", totalBytes)
+	}
+	if validBits < 1 || validBits > uint(totalBytes)*8 {
+		return 0, fmt.Errorf("valid bits %d out of range 1..%d",
+			validBits, totalBytes*8)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf, _, err := v.readRegBytes(reg, totalBytes)
+	if err != nil {
+		return 0, err
+	}
+	u := decodeUint(buf, order) & (1<<validBits - 1)
+	w := int32(signExtend(u, validBits))
+	v.log.Debugf("Read S%d %d from reg 0x%0X", validBits, w, reg)
+	return w, nil
+}
+
+// readRegUint reads width bytes (1..8) from I2C-device starting from
+// address specified in reg and decode them as unsigned integer
+// with byte order specified in order. Connection lock must be held.
+func (v *I2C) readRegUint(reg byte, width int, order binary.ByteOrder) (uint64, error) {
+	if width < 1 || width > 8 {
+		return 0, fmt.Errorf("width %d out of range 1..8", width)
+	}
+	buf, _, err := v.readRegBytes(reg, width)
+	if err != nil {
+		return 0, err
+	}
+	return decodeUint(buf, order), nil
+}
+
+// ReadRegDuration reads unsigned counter of width bytes (1..8)
+// from I2C-device starting from address specified in reg, with byte
+// order specified in order, and convert it to duration, multiplying
+// by tick (period of device timer clock).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegDuration(reg byte, width int, order binary.ByteOrder,
+	tick time.Duration) (time.Duration, error) {
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	u, err := v.readRegUint(reg, width, order)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(u) * tick
+	v.log.Debugf("Read duration %v (%d ticks) from reg 0x%0X", d, u, reg)
+	return d, nil
+}
+
+// ReadRegTime reads unsigned counter of width bytes (1..8) from
+// I2C-device starting from address specified in reg, with byte order
+// specified in order, and convert it to time, counting tick periods
+// from epoch (seconds since Unix epoch for RTC, for instance:
+// epoch = time.Unix(0, 0), tick = time.Second).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegTime(reg byte, width int, order binary.ByteOrder,
+	epoch time.Time, tick time.Duration) (time.Time, error) {
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	u, err := v.readRegUint(reg, width, order)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := epoch.Add(time.Duration(u) * tick)
+	v.log.Debugf("Read time %v (%d ticks) from reg 0x%0X", t, u, reg)
+	return t, nil
+}
+
+// encodeUint encode lower len(buf) bytes of u to buf
+// with byte order specified in order.
+func encodeUint(buf []byte, u uint64, order binary.ByteOrder) {
+	if isBigEndian(order) {
+		for i := len(buf) - 1; i >= 0; i-- {
+			buf[i] = byte(u)
+			u >>= 8
+		}
+	} else {
+		for i := range buf {
+			buf[i] = byte(u)
+			u >>= 8
+		}
+	}
+}
+
+// writeRegUint writes lower width bytes (1..8) of u to I2C-device
+// starting from address specified in reg, with byte order specified
+// in order. Connection lock must be held.
+func (v *I2C) writeRegUint(reg byte, width int, u uint64, order binary.ByteOrder) error {
+	if width < 1 || width > 8 {
+		return fmt.Errorf("width %d out of range 1..8", width)
+	}
+	buf := make([]byte, width+1)
+	buf[0] = reg
+	encodeUint(buf[1:], u, order)
+	if _, err := v.writeBytes(nextTx(), buf); err != nil {
+		return v.regError("write", reg, err)
+	}
+	return nil
+}
+
+// ReadRegRatio reads unsigned words (16 bits) numerator and denominator
+// from I2C-device registers numReg and denReg under one connection lock,
+// with byte order specified in order, and returns their ratio.
+// Returns ErrDivideByZero, if denominator is zero.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegRatio(numReg, denReg byte, order binary.ByteOrder) (float64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	num, err := v.readRegUint(numReg, 2, order)
+	if err != nil {
+		return 0, err
+	}
+	den, err := v.readRegUint(denReg, 2, order)
+	if err != nil {
+		return 0, err
+	}
+	if den == 0 {
+		return 0, ErrDivideByZero
+	}
+	ratio := float64(num) / float64(den)
+	v.log.Debugf("Read ratio %d/%d from regs 0x%0X/0x%0X", num, den, numReg, denReg)
+	return ratio, nil
+}
+
+// ReadRegStrided reads count values of width bytes (1..4) from I2C-device,
+// starting from start address and stepping stride registers between
+// values (separate reads under one connection lock), decoding them
+// with byte order specified in order. Handles sparse register arrays,
+// where values are stored every other register, for instance.
+// Values are returned as is, without sign extension.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegStrided(start byte, count int, stride int, width int,
+	order binary.ByteOrder) ([]int32, error) {
+
+	if width < 1 || width > 4 {
+		return nil, fmt.Errorf("width %d out of range 1..4", width)
+	}
+	if count < 0 || stride < 1 {
+		return nil, fmt.Errorf("invalid count %d or stride %d", count, stride)
+	}
+	if count > 0 && int(start)+(count-1)*stride+width > 0x100 {
+		return nil, fmt.Errorf("%d values with stride %d starting from reg 0x%0X "+
+			"exceed register space", count, stride, start)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	values := make([]int32, count)
+	for i := range values {
+		reg := start + byte(i*stride)
+		u, err := v.readRegUint(reg, width, order)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = int32(u)
+	}
+	return values, nil
+}
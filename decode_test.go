@@ -0,0 +1,196 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadRegSignedBits(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	tests := []struct {
+		data      []byte
+		validBits uint
+		order     binary.ByteOrder
+		want      int32
+	}{
+		// 12-bit values around sign boundary.
+		{[]byte{0x07, 0xFF}, 12, binary.BigEndian, 2047},
+		{[]byte{0x08, 0x00}, 12, binary.BigEndian, -2048},
+		{[]byte{0x0F, 0xFF}, 12, binary.BigEndian, -1},
+		// Bits above validBits are masked.
+		{[]byte{0xF7, 0xFF}, 12, binary.BigEndian, 2047},
+		{[]byte{0x00, 0x08}, 12, binary.LittleEndian, -2048},
+		// 18-bit values around sign boundary.
+		{[]byte{0x01, 0xFF, 0xFF}, 18, binary.BigEndian, 131071},
+		{[]byte{0x02, 0x00, 0x00}, 18, binary.BigEndian, -131072},
+		{[]byte{0x03, 0xFF, 0xFF}, 18, binary.BigEndian, -1},
+		{[]byte{0x00, 0x00, 0x02}, 18, binary.LittleEndian, -131072},
+	}
+	for _, test := range tests {
+		copy(c.regs[0x00:], test.data)
+		got, err := v.ReadRegSignedBits(0x00, len(test.data), test.validBits, test.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("[% X] %d bits %v: got %d, want %d",
+				test.data, test.validBits, test.order, got, test.want)
+		}
+	}
+	if _, err := v.ReadRegSignedBits(0x00, 2, 17, binary.BigEndian); err == nil {
+		t.Error("valid bits exceeding total bytes accepted")
+	}
+}
+
+func TestReadRegDuration(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	copy(a.chip(0x48).regs[0x30:], []byte{0x01, 0xF4})
+	d, err := v.ReadRegDuration(0x30, 2, binary.BigEndian, 100*time.Microsecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 500 ticks of 100 us.
+	if d != 50*time.Millisecond {
+		t.Errorf("ReadRegDuration = %v, want 50ms", d)
+	}
+}
+
+func TestReadRegRatio(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	copy(c.regs[0x40:], []byte{0x00, 0x03, 0x00, 0x04})
+	ratio, err := v.ReadRegRatio(0x40, 0x42, binary.BigEndian)
+	if err != nil || ratio != 0.75 {
+		t.Errorf("ReadRegRatio = %v, %v, want 0.75", ratio, err)
+	}
+	copy(c.regs[0x42:], []byte{0x00, 0x00})
+	if _, err := v.ReadRegRatio(0x40, 0x42, binary.BigEndian); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("expected ErrDivideByZero, got %v", err)
+	}
+}
+
+func TestReadRegWithOrder(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	copy(a.chip(0x48).regs[0x00:], []byte{0xFF, 0xFE, 0x12, 0x34})
+	if u, err := v.ReadRegU16(0x00, binary.LittleEndian); err != nil || u != 0xFEFF {
+		t.Errorf("ReadRegU16 LE = 0x%04X, %v", u, err)
+	}
+	if s, err := v.ReadRegS16(0x00, binary.LittleEndian); err != nil || s != -257 {
+		t.Errorf("ReadRegS16 LE = %d, %v", s, err)
+	}
+	if u, err := v.ReadRegU32(0x00, binary.LittleEndian); err != nil || u != 0x3412FEFF {
+		t.Errorf("ReadRegU32 LE = 0x%08X, %v", u, err)
+	}
+	if s, err := v.ReadRegS32(0x00, binary.BigEndian); err != nil || s != -0x1EDCC {
+		t.Errorf("ReadRegS32 BE = %d, %v", s, err)
+	}
+}
+
+func TestRegWordOrders(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	tests := []struct {
+		order binary.ByteOrder
+		u16   []byte
+		u32   []byte
+	}{
+		{binary.BigEndian, []byte{0x12, 0x34}, []byte{0x89, 0xAB, 0xCD, 0xEF}},
+		{binary.LittleEndian, []byte{0x34, 0x12}, []byte{0xEF, 0xCD, 0xAB, 0x89}},
+	}
+	for _, test := range tests {
+		a.reset()
+		if err := v.WriteRegU16(0x10, 0x1234, test.order); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.WriteRegU32(0x20, 0x89ABCDEF, test.order); err != nil {
+			t.Fatal(err)
+		}
+		w := a.writes()
+		if len(w) != 2 || !bytes.Equal(w[0], append([]byte{0x10}, test.u16...)) ||
+			!bytes.Equal(w[1], append([]byte{0x20}, test.u32...)) {
+			t.Errorf("%v: written %X", test.order, w)
+		}
+		if !bytes.Equal(c.regs[0x10:0x12], test.u16) || !bytes.Equal(c.regs[0x20:0x24], test.u32) {
+			t.Errorf("%v: stored [% X] [% X]", test.order, c.regs[0x10:0x12], c.regs[0x20:0x24])
+		}
+		if u, err := v.ReadRegU16(0x10, test.order); err != nil || u != 0x1234 {
+			t.Errorf("%v: ReadRegU16 = 0x%04X, %v", test.order, u, err)
+		}
+		if s, err := v.ReadRegS16(0x10, test.order); err != nil || s != 0x1234 {
+			t.Errorf("%v: ReadRegS16 = %d, %v", test.order, s, err)
+		}
+		if u, err := v.ReadRegU32(0x20, test.order); err != nil || u != 0x89ABCDEF {
+			t.Errorf("%v: ReadRegU32 = 0x%08X, %v", test.order, u, err)
+		}
+		if s, err := v.ReadRegS32(0x20, test.order); err != nil || s != -0x76543211 {
+			t.Errorf("%v: ReadRegS32 = %d, %v", test.order, s, err)
+		}
+	}
+	// Named methods match generic ones.
+	if u, err := v.ReadRegU32LE(0x20); err != nil || u != 0x89ABCDEF {
+		t.Errorf("ReadRegU32LE = 0x%08X, %v", u, err)
+	}
+	if s, err := v.ReadRegS16BE(0x10); err != nil || s != 0x3412 {
+		t.Errorf("ReadRegS16BE = %d, %v", s, err)
+	}
+}
+
+func TestReadRegStrided(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	for i := 0; i < 8; i++ {
+		c.regs[0x20+i] = byte(0x10 + i)
+	}
+	values, err := v.ReadRegStrided(0x20, 3, 3, 2, binary.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{0x1110, 0x1413, 0x1716}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values %X, want %X", values, want)
+	}
+	want := [][]byte{{0x20}, {0x23}, {0x26}}
+	if w := a.writes(); !reflect.DeepEqual(w, want) {
+		t.Errorf("registers selected %X, want %X", w, want)
+	}
+	for _, r := range a.operations("read") {
+		if len(r.data) != 2 {
+			t.Errorf("read of %d bytes, want 2", len(r.data))
+		}
+	}
+
+	if _, err := v.ReadRegStrided(0xF0, 4, 6, 1, binary.BigEndian); err == nil {
+		t.Error("register space overflow accepted")
+	}
+	if _, err := v.ReadRegStrided(0x00, 2, 0, 1, binary.BigEndian); err == nil {
+		t.Error("zero stride accepted")
+	}
+}
+
+func TestReadRegTime(t *testing.T) {
+	v, a, _ := newFake(t, 0x68)
+	c := a.chip(0x68)
+	// 1600000000 seconds since Unix epoch, little endian.
+	copy(c.regs[0x00:], []byte{0x00, 0x10, 0x5E, 0x5F})
+	got, err := v.ReadRegTime(0x00, 4, binary.LittleEndian, time.Unix(0, 0), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1600000000, 0); !got.Equal(want) {
+		t.Errorf("ReadRegTime = %v, want %v", got, want)
+	}
+	// 3000 ticks of 10 ms since custom epoch.
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	copy(c.regs[0x10:], []byte{0x0B, 0xB8})
+	got, err = v.ReadRegTime(0x10, 2, binary.BigEndian, epoch, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := epoch.Add(30 * time.Second); !got.Equal(want) {
+		t.Errorf("ReadRegTime = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package i2c
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Decoder converts raw bytes read from I2C-device
+// to device-specific value (measurement structure, for instance).
+type Decoder func(data []byte) (interface{}, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[byte]Decoder)
+)
+
+// RegisterDecoder associates decoder with device ID (value of device
+// identification register, like "WHO_AM_I"), so ReadDecoded can apply
+// it to data of self-identifying devices. Registering decoder for ID
+// already taken replaces previous one; nil decoder removes registration.
+// Normally called from init function of device driver package.
+func RegisterDecoder(id byte, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if decoder == nil {
+		delete(decoders, id)
+		return
+	}
+	decoders[id] = decoder
+}
+
+// lookupDecoder returns decoder registered for device ID.
+func lookupDecoder(id byte) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	decoder, ok := decoders[id]
+	return decoder, ok
+}
+
+// ReadDecoded reads device ID from register idReg, then reads n bytes
+// starting from register dataReg and decodes them with decoder
+// registered for this ID by RegisterDecoder. Returns ErrUnknownValue,
+// if no decoder registered for device ID.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadDecoded(idReg byte, dataReg byte, n int) (interface{}, error) {
+	v.mu.Lock()
+	id, err := v.readRegU8(idReg)
+	if err != nil {
+		v.mu.Unlock()
+		return nil, err
+	}
+	decoder, ok := lookupDecoder(id)
+	if !ok {
+		v.mu.Unlock()
+		return nil, fmt.Errorf("%w: no decoder registered for device ID 0x%0X",
+			ErrUnknownValue, id)
+	}
+	buf, c, err := v.readRegBytes(dataReg, n)
+	v.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return decoder(buf[:c])
+}
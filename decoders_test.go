@@ -0,0 +1,40 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+type fakeReading struct {
+	Temp int16
+}
+
+func TestReadDecoded(t *testing.T) {
+	RegisterDecoder(0x60, func(data []byte) (interface{}, error) {
+		return fakeReading{Temp: int16(binary.BigEndian.Uint16(data))}, nil
+	})
+	t.Cleanup(func() { RegisterDecoder(0x60, nil) })
+
+	v, a, _ := newFake(t, 0x76)
+	c := a.chip(0x76)
+	c.regs[0xD0] = 0x60
+	copy(c.regs[0xFA:], []byte{0xFF, 0x38})
+	value, err := v.ReadDecoded(0xD0, 0xFA, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := value.(fakeReading); !ok || r.Temp != -200 {
+		t.Errorf("decoded %#v, want fakeReading{Temp: -200}", value)
+	}
+
+	c.regs[0xD0] = 0x58
+	if _, err := v.ReadDecoded(0xD0, 0xFA, 2); !errors.Is(err, ErrUnknownValue) {
+		t.Errorf("expected ErrUnknownValue, got %v", err)
+	}
+	// Removed decoder isn't found anymore.
+	RegisterDecoder(0x60, nil)
+	if _, ok := lookupDecoder(0x60); ok {
+		t.Error("decoder not removed")
+	}
+}
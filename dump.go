@@ -0,0 +1,67 @@
+package i2c
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DumpRegisters reads count consecutive registers from I2C-device
+// starting from start address in one read (combined transaction,
+// if enabled with WithCombinedReads) and returns their values:
+// element i holds value of register start+i.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) DumpRegisters(start byte, count int) ([]byte, error) {
+	if count < 0 || int(start)+count > 0x100 {
+		return nil, fmt.Errorf("register range 0x%0X+%d exceeds 0xFF", start, count)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf, _, err := v.readRegBytes(start, count)
+	return buf, err
+}
+
+// DumpRegistersString reads count consecutive registers from I2C-device
+// starting from start address, like DumpRegisters, and formats them
+// as table similar to "hexdump -C" output: register address, 16 values
+// in hex per line and their printable ASCII representation.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) DumpRegistersString(start byte, count int) (string, error) {
+	buf, err := v.DumpRegisters(start, count)
+	if err != nil {
+		return "", err
+	}
+	return formatDump(start, buf), nil
+}
+
+// formatDump format register values buf, starting from
+// register start, as "hexdump -C" like table.
+func formatDump(start byte, buf []byte) string {
+	var sb strings.Builder
+	for offset := 0; offset < len(buf); offset += 16 {
+		line := buf[offset:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+		fmt.Fprintf(&sb, "%02X  ", int(start)+offset)
+		for i := 0; i < 16; i++ {
+			if i == 8 {
+				sb.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(&sb, "%02X ", line[i])
+			} else {
+				sb.WriteString("   ")
+			}
+		}
+		sb.WriteString(" |")
+		for _, b := range line {
+			if b >= 0x20 && b < 0x7F {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteString("|\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,44 @@
+package i2c
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDumpRegistersString(t *testing.T) {
+	v, a, _ := newFake(t, 0x50)
+	copy(a.chip(0x50).regs[0x10:], []byte("Hello, I2C!\x00\x01\x7F\xFE\x20"))
+	got, err := v.DumpRegistersString(0x10, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "10  48 65 6C 6C 6F 2C 20 49  32 43 21 00 01 7F FE 20  |Hello, I2C!.... |\n"
+	if got != want {
+		t.Errorf("dump\n%q, want\n%q", got, want)
+	}
+	// Single read of the whole range.
+	if ops := a.operations("read"); len(ops) != 1 || len(ops[0].data) != 16 {
+		t.Errorf("reads %+v, want one 16 byte read", ops)
+	}
+}
+
+func TestFormatDumpPartial(t *testing.T) {
+	buf := bytes.Repeat([]byte{'A'}, 18)
+	// Short last line is padded, so ASCII column stays aligned.
+	want := "00  41 41 41 41 41 41 41 41  41 41 41 41 41 41 41 41  |AAAAAAAAAAAAAAAA|\n" +
+		"10  41 41 " + strings.Repeat("   ", 6) + " " + strings.Repeat("   ", 8) + " |AA|\n"
+	if got := formatDump(0x00, buf); got != want {
+		t.Errorf("dump\n%q, want\n%q", got, want)
+	}
+}
+
+func TestDumpRegistersRange(t *testing.T) {
+	v, _, _ := newFake(t, 0x50)
+	if _, err := v.DumpRegisters(0xF8, 9); err == nil {
+		t.Error("range past 0xFF accepted")
+	}
+	if buf, err := v.DumpRegisters(0xF8, 8); err != nil || len(buf) != 8 {
+		t.Errorf("DumpRegisters(0xF8, 8) = [% X], %v", buf, err)
+	}
+}
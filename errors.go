@@ -0,0 +1,85 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var (
+	// ErrUnsupportedFunc returned when I2C adapter
+	// lacks functionality required for operation.
+	ErrUnsupportedFunc = errors.New("i2c: unsupported adapter functionality")
+	// ErrTimeout returned when polled condition
+	// hasn't been met within allowed time or attempts.
+	ErrTimeout = errors.New("i2c: timeout")
+	// ErrInvalidValue returned when value is rejected
+	// before it is written to I2C-device.
+	ErrInvalidValue = errors.New("i2c: invalid value")
+	// ErrDivideByZero returned when ratio
+	// denominator read from I2C-device is zero.
+	ErrDivideByZero = errors.New("i2c: divide by zero")
+	// ErrDeviceNotFound returned when bus scan
+	// hasn't found requested I2C-device.
+	ErrDeviceNotFound = errors.New("i2c: device not found")
+	// ErrUnknownValue returned when value read from
+	// I2C-device is missing in lookup table.
+	ErrUnknownValue = errors.New("i2c: unknown value")
+	// ErrPECMismatch returned when packet error checking
+	// byte received from I2C-device doesn't match data.
+	ErrPECMismatch = errors.New("i2c: PEC mismatch")
+	// ErrDeviceNotPresent matches (with errors.Is) transfer errors,
+	// which signal that I2C-device doesn't respond at its address
+	// (ENXIO) or adapter has gone (ENODEV).
+	ErrDeviceNotPresent = errors.New("i2c: device not present")
+	// ErrDryRun returned by operations based on ioctl calls
+	// (combined transactions, SMBus commands, adapter settings),
+	// which can't be simulated in dry-run mode (see WithDryRun).
+	ErrDryRun = errors.New("i2c: not supported in dry-run mode")
+)
+
+// OpError describe failed I2C-device operation: operation name,
+// bus, device address and register involved. Underlying error
+// (usually syscall.Errno) is available via errors.Is and errors.As.
+type OpError struct {
+	// Op is an operation name, like "read reg" or "write".
+	Op   string
+	Bus  int
+	Addr uint16
+	// Reg is register address, or -1 if operation doesn't involve one.
+	Reg int
+	Err error
+}
+
+func (e *OpError) Error() string {
+	if e.Reg < 0 {
+		return fmt.Sprintf("i2c: %s on bus %d addr 0x%02X: %v", e.Op, e.Bus, e.Addr, e.Err)
+	}
+	return fmt.Sprintf("i2c: %s 0x%02X on bus %d addr 0x%02X: %v",
+		e.Op, e.Reg, e.Bus, e.Addr, e.Err)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// Is make ErrDeviceNotPresent match ENXIO and ENODEV errors.
+func (e *OpError) Is(target error) bool {
+	return target == ErrDeviceNotPresent &&
+		(errors.Is(e.Err, syscall.ENXIO) || errors.Is(e.Err, syscall.ENODEV))
+}
+
+// opError wrap err of operation op with connection context.
+func (v *I2C) opError(op string, err error) error {
+	return &OpError{Op: op, Bus: v.bus, Addr: v.GetAddr16(), Reg: -1, Err: err}
+}
+
+// regError wrap err of operation op on register reg with connection
+// context, replacing context of lower level operation, if any.
+func (v *I2C) regError(op string, reg byte, err error) error {
+	var e *OpError
+	if errors.As(err, &e) {
+		err = e.Err
+	}
+	return &OpError{Op: op + " reg", Bus: v.bus, Addr: v.GetAddr16(), Reg: int(reg), Err: err}
+}
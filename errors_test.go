@@ -0,0 +1,53 @@
+package i2c
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestOpError(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	quietLog(t)
+
+	a.failNext(syscall.EIO)
+	_, err := v.ReadRegU16BE(0x10)
+	if !errors.Is(err, syscall.EIO) {
+		t.Fatalf("error %v doesn't match EIO", err)
+	}
+	var e *OpError
+	if !errors.As(err, &e) {
+		t.Fatalf("error %T isn't *OpError", err)
+	}
+	if e.Op != "read reg" || e.Bus != 1 || e.Addr != 0x48 || e.Reg != 0x10 {
+		t.Errorf("error context %+v", e)
+	}
+	if want := "i2c: read reg 0x10 on bus 1 addr 0x48: " + syscall.EIO.Error(); err.Error() != want {
+		t.Errorf("message %q, want %q", err, want)
+	}
+	if errors.Is(err, ErrDeviceNotPresent) {
+		t.Error("EIO matches ErrDeviceNotPresent")
+	}
+
+	// Register isn't reported by raw transfers.
+	a.failNext(syscall.EIO)
+	_, err = v.WriteBytes([]byte{0x01})
+	if !errors.As(err, &e) || e.Reg != -1 {
+		t.Fatalf("error %v, want *OpError without register", err)
+	}
+	if want := "i2c: write on bus 1 addr 0x48: " + syscall.EIO.Error(); err.Error() != want {
+		t.Errorf("message %q, want %q", err, want)
+	}
+
+	for _, errno := range []syscall.Errno{syscall.ENXIO, syscall.ENODEV} {
+		a.failNext(errno)
+		err := v.WriteRegU8(0x20, 0x01)
+		if !errors.Is(err, ErrDeviceNotPresent) || !errors.Is(err, errno) {
+			t.Errorf("%v doesn't match both ErrDeviceNotPresent and errno", err)
+		}
+		// Register level wrap replaces, not nests, transfer context.
+		if errors.As(err, &e) && errors.As(e.Err, new(*OpError)) {
+			t.Errorf("nested OpError in %v", err)
+		}
+	}
+}
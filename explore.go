@@ -0,0 +1,111 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ProbeReg reads register specified in reg twice and report
+// whether it looks readable: both reads acknowledged
+// and returned the same value. Value read is returned as well.
+//
+// This is a heuristic, intended for interactive register map
+// exploration only: write-only registers often return stale
+// or constant data, which can't be distinguished from real one,
+// while volatile registers (counters, sensor data) may return
+// different values and be reported as not readable.
+// Register not acknowledged (NACK) is reported as not readable
+// with nil error, while other bus failures (device gone, adapter
+// timeout) are returned as error.
+func (v *I2C) ProbeReg(reg byte) (readable bool, value byte, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	b1, err := v.readRegU8(reg)
+	if err != nil {
+		return false, 0, v.probeRegError(reg, err)
+	}
+	b2, err := v.readRegU8(reg)
+	if err != nil {
+		return false, 0, v.probeRegError(reg, err)
+	}
+	if b1 != b2 {
+		v.log.Debugf("Reg 0x%0X unstable: 0x%0X != 0x%0X", reg, b1, b2)
+		return false, 0, nil
+	}
+	return true, b1, nil
+}
+
+// probeRegError return nil for register read error err caused
+// by NACK, and err itself otherwise.
+func (v *I2C) probeRegError(reg byte, err error) error {
+	if isNak(err) {
+		v.log.Debugf("Reg 0x%0X doesn't respond: %v", reg, err)
+		return nil
+	}
+	return err
+}
+
+// DetectEndian reads 2 bytes from I2C-device register specified in reg,
+// where device keeps known magic word, and detect device byte order
+// comparing them with magic. Returns binary.BigEndian or
+// binary.LittleEndian respectively, either error if none match.
+// For symmetric magic (like 0xA5A5) binary.BigEndian is returned.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) DetectEndian(reg byte, magic uint16) (binary.ByteOrder, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf, _, err := v.readRegBytes(reg, 2)
+	if err != nil {
+		return nil, err
+	}
+	switch magic {
+	case binary.BigEndian.Uint16(buf):
+		v.log.Debugf("Detect big endian byte order from reg 0x%0X", reg)
+		return binary.BigEndian, nil
+	case binary.LittleEndian.Uint16(buf):
+		v.log.Debugf("Detect little endian byte order from reg 0x%0X", reg)
+		return binary.LittleEndian, nil
+	}
+	return nil, fmt.Errorf("magic 0x%04X doesn't match data [% X] read from reg 0x%0X",
+		magic, buf, reg)
+}
+
+// DetectAutoIncrement detect, whether I2C-device auto-increments
+// register pointer on multi-byte reads: reads two bytes as a block
+// starting from reg, then reads registers reg and reg+1 individually
+// and compares results. Block matching individual reads means
+// auto-increment, block repeating the first register means none.
+//
+// This is a heuristic for bring-up diagnostics: registers reg and
+// reg+1 should hold different and stable values, otherwise
+// result is unreliable (error is returned, if reads are ambiguous).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) DetectAutoIncrement(reg byte) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	block, _, err := v.readRegBytes(reg, 2)
+	if err != nil {
+		return false, err
+	}
+	b0, err := v.readRegU8(reg)
+	if err != nil {
+		return false, err
+	}
+	b1, err := v.readRegU8(reg + 1)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case b0 == b1:
+		return false, fmt.Errorf("regs 0x%0X and 0x%0X hold the same value 0x%0X, "+
+			"can't detect auto-increment", reg, reg+1, b0)
+	case block[0] == b0 && block[1] == b1:
+		v.log.Debugf("Auto-increment detected at reg 0x%0X", reg)
+		return true, nil
+	case block[0] == b0 && block[1] == b0:
+		v.log.Debugf("No auto-increment detected at reg 0x%0X", reg)
+		return false, nil
+	}
+	return false, fmt.Errorf("block [% X] doesn't match individual reads [%0X %0X], "+
+		"can't detect auto-increment", block, b0, b1)
+}
@@ -0,0 +1,84 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestProbeReg(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	c.regs[0x05] = 0x5A
+	readable, value, err := v.ProbeReg(0x05)
+	if err != nil || !readable || value != 0x5A {
+		t.Errorf("stable reg: readable=%v value=0x%02X err=%v", readable, value, err)
+	}
+
+	c.nak = 1
+	readable, _, err = v.ProbeReg(0x05)
+	if err != nil || readable {
+		t.Errorf("erroring reg: readable=%v err=%v", readable, err)
+	}
+
+	a.failNext(syscall.ETIMEDOUT)
+	readable, _, err = v.ProbeReg(0x05)
+	if !errors.Is(err, syscall.ETIMEDOUT) || readable {
+		t.Errorf("bus failure: readable=%v err=%v", readable, err)
+	}
+
+	c.onRead = func(c *fakeChip) { c.regs[0x05]++ }
+	readable, _, err = v.ProbeReg(0x05)
+	if err != nil || readable {
+		t.Errorf("unstable reg: readable=%v err=%v", readable, err)
+	}
+}
+
+func TestDetectEndian(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	tests := []struct {
+		data []byte
+		want binary.ByteOrder
+	}{
+		{[]byte{0x12, 0x34}, binary.BigEndian},
+		{[]byte{0x34, 0x12}, binary.LittleEndian},
+		{[]byte{0x12, 0x00}, nil},
+	}
+	for _, test := range tests {
+		copy(c.regs[0x0F:], test.data)
+		order, err := v.DetectEndian(0x0F, 0x1234)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("[% X]: no error for mismatching magic", test.data)
+			}
+			continue
+		}
+		if err != nil || order != test.want {
+			t.Errorf("[% X]: got %v, %v, want %v", test.data, order, err, test.want)
+		}
+	}
+}
+
+func TestDetectAutoIncrement(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	copy(c.regs[0x28:], []byte{0x12, 0x34})
+	inc, err := v.DetectAutoIncrement(0x28)
+	if err != nil || !inc {
+		t.Errorf("auto-incrementing chip: got %v, %v", inc, err)
+	}
+
+	c.noInc = true
+	inc, err = v.DetectAutoIncrement(0x28)
+	if err != nil || inc {
+		t.Errorf("non-incrementing chip: got %v, %v", inc, err)
+	}
+
+	// Equal neighbour registers make result ambiguous.
+	c.regs[0x29] = 0x12
+	if _, err := v.DetectAutoIncrement(0x28); err == nil {
+		t.Error("no error for ambiguous registers")
+	}
+}
@@ -0,0 +1,476 @@
+package i2c
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// fakeClock is a Clock, which time moves only by Sleep and After
+// calls: they return immediately, advancing time by requested duration.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.now = c.now.Add(d)
+		c.sleeps = append(c.sleeps, d)
+	}
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+// Advance move time forward by d without recording sleep.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Slept return sleeps done so far.
+func (c *fakeClock) Slept() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// fakeChip is register-map device on fake adapter: first addrLen
+// bytes of write set register pointer, the rest are stored starting
+// from pointer, and reads return bytes from pointer, both with
+// pointer auto-increment (unless noInc is set for reads).
+type fakeChip struct {
+	regs    [0x10000]byte
+	addrLen int
+	ptr     int
+	// read repeats register at pointer
+	noInc bool
+	// number of next transfers to NAK
+	nak int
+	// called before every read from chip
+	onRead func(c *fakeChip)
+	// called after every write to chip
+	onWrite func(c *fakeChip)
+}
+
+func (c *fakeChip) write(data []byte) {
+	if len(data) < c.addrLen {
+		return
+	}
+	ptr := 0
+	for _, b := range data[:c.addrLen] {
+		ptr = ptr<<8 | int(b)
+	}
+	c.ptr = ptr
+	for _, b := range data[c.addrLen:] {
+		c.regs[c.ptr&0xFFFF] = b
+		c.ptr++
+	}
+	if c.onWrite != nil {
+		c.onWrite(c)
+	}
+}
+
+func (c *fakeChip) read(buf []byte) {
+	if c.onRead != nil {
+		c.onRead(c)
+	}
+	for i := range buf {
+		buf[i] = c.regs[c.ptr&0xFFFF]
+		if !c.noInc {
+			c.ptr++
+		}
+	}
+}
+
+// fakeOp is an operation recorded by fake adapter.
+type fakeOp struct {
+	// "write", "read", "ioctl", "smbus"; I2C_RDWR messages
+	// are recorded as "write" and "read" with rdwr set.
+	kind  string
+	rdwr  bool
+	addr  uint16
+	flags uint16
+	cmd   uintptr
+	arg   uintptr
+	data  []byte
+	at    time.Time
+}
+
+// fakeAdapter emulates I2C adapter with chips attached,
+// serving i2c-dev transfers and ioctl calls.
+type fakeAdapter struct {
+	mu    sync.Mutex
+	chips map[uint16]*fakeChip
+	// addresses claimed by kernel driver
+	busy  map[uint16]bool
+	funcs uint32
+	clock Clock
+	ops   []fakeOp
+	// errors returned by next transfers
+	fail []error
+	// number of files opened
+	opens int
+	// don't record operations (benchmarks)
+	norecord bool
+}
+
+func newFakeAdapter() *fakeAdapter {
+	return &fakeAdapter{
+		chips: make(map[uint16]*fakeChip),
+		busy:  make(map[uint16]bool),
+		funcs: I2C_FUNC_I2C | I2C_FUNC_10BIT_ADDR | I2C_FUNC_SMBUS_PEC |
+			I2C_FUNC_SMBUS_QUICK | I2C_FUNC_SMBUS_READ_BYTE |
+			I2C_FUNC_SMBUS_WRITE_BYTE | I2C_FUNC_SMBUS_READ_BLOCK_DATA |
+			I2C_FUNC_SMBUS_WRITE_BLOCK_DATA,
+	}
+}
+
+// chip return chip at address addr, attaching new one if absent.
+func (a *fakeAdapter) chip(addr uint16) *fakeChip {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.chips[addr]
+	if !ok {
+		c = &fakeChip{addrLen: 1}
+		a.chips[addr] = c
+	}
+	return c
+}
+
+// open return new file of adapter.
+func (a *fakeAdapter) open() *fakeFile {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.opens++
+	return &fakeFile{a: a}
+}
+
+// failNext make next transfers fail with errs, one by one.
+func (a *fakeAdapter) failNext(errs ...error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.fail = append(a.fail, errs...)
+}
+
+// record append op to operation log, copying its data.
+// Lock must be held.
+func (a *fakeAdapter) record(op fakeOp) {
+	if a.norecord {
+		return
+	}
+	op.data = append([]byte(nil), op.data...)
+	if a.clock != nil {
+		op.at = a.clock.Now()
+	}
+	a.ops = append(a.ops, op)
+}
+
+// operations return copy of operation log, filtered by kind,
+// if any specified.
+func (a *fakeAdapter) operations(kinds ...string) []fakeOp {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var ops []fakeOp
+	for _, op := range a.ops {
+		if len(kinds) == 0 {
+			ops = append(ops, op)
+			continue
+		}
+		for _, kind := range kinds {
+			if op.kind == kind {
+				ops = append(ops, op)
+				break
+			}
+		}
+	}
+	return ops
+}
+
+// writes return data of all write operations.
+func (a *fakeAdapter) writes() [][]byte {
+	var writes [][]byte
+	for _, op := range a.operations("write") {
+		writes = append(writes, op.data)
+	}
+	return writes
+}
+
+// reset clear operation log.
+func (a *fakeAdapter) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ops = nil
+}
+
+// target return chip addressed by transfer, or error.
+// Lock must be held.
+func (a *fakeAdapter) target(addr uint16) (*fakeChip, error) {
+	if len(a.fail) > 0 {
+		err := a.fail[0]
+		a.fail = a.fail[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	c, ok := a.chips[addr]
+	if !ok {
+		return nil, syscall.ENXIO
+	}
+	if c.nak > 0 {
+		c.nak--
+		return nil, syscall.ENXIO
+	}
+	return c, nil
+}
+
+// fakeFile is an open file of fake adapter, with its own slave address,
+// as i2c-dev files have.
+type fakeFile struct {
+	a       *fakeAdapter
+	addr    uint16
+	tenBit  bool
+	pec     bool
+	timeout uintptr
+	closed  bool
+}
+
+func (f *fakeFile) Read(buf []byte) (int, error) {
+	a := f.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	c, err := a.target(f.addr)
+	if err != nil {
+		return 0, err
+	}
+	c.read(buf)
+	a.record(fakeOp{kind: "read", addr: f.addr, data: buf})
+	return len(buf), nil
+}
+
+func (f *fakeFile) Write(buf []byte) (int, error) {
+	a := f.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	c, err := a.target(f.addr)
+	if err != nil {
+		return 0, err
+	}
+	c.write(buf)
+	a.record(fakeOp{kind: "write", addr: f.addr, data: buf})
+	return len(buf), nil
+}
+
+func (f *fakeFile) Close() error {
+	f.a.mu.Lock()
+	defer f.a.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *fakeFile) Fd() uintptr {
+	return ^uintptr(0)
+}
+
+func (f *fakeFile) ioctl(cmd, arg uintptr) error {
+	a := f.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.record(fakeOp{kind: "ioctl", cmd: cmd, arg: arg})
+	switch cmd {
+	case I2C_SLAVE, I2C_SLAVE_FORCE:
+		if cmd == I2C_SLAVE && a.busy[uint16(arg)] {
+			return syscall.EBUSY
+		}
+		f.addr = uint16(arg)
+	case I2C_TENBIT:
+		if arg != 0 && a.funcs&I2C_FUNC_10BIT_ADDR == 0 {
+			return syscall.EOPNOTSUPP
+		}
+		f.tenBit = arg != 0
+	case I2C_PEC:
+		f.pec = arg != 0
+	case I2C_TIMEOUT:
+		f.timeout = arg
+	case I2C_RETRIES:
+	default:
+		return syscall.ENOTTY
+	}
+	return nil
+}
+
+func (f *fakeFile) ioctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	switch cmd {
+	case I2C_FUNCS:
+		f.a.mu.Lock()
+		defer f.a.mu.Unlock()
+		f.a.record(fakeOp{kind: "ioctl", cmd: cmd})
+		*(*uintptr)(arg) = uintptr(f.a.funcs)
+		return nil
+	case I2C_RDWR:
+		return f.rdwr((*i2cRdwrIoctlData)(arg))
+	case I2C_SMBUS:
+		return f.smbus((*i2cSmbusIoctlData)(arg))
+	}
+	return syscall.ENOTTY
+}
+
+// msgData return buffer of kernel message m.
+func msgData(m *i2cMsg) []byte {
+	if m.buf == nil {
+		return nil
+	}
+	return (*[0x10000]byte)(unsafe.Pointer(m.buf))[:m.len:m.len]
+}
+
+func (f *fakeFile) rdwr(d *i2cRdwrIoctlData) error {
+	a := f.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if d.nmsgs > I2C_RDWR_IOCTL_MAX_MSGS {
+		return syscall.EINVAL
+	}
+	msgs := (*[I2C_RDWR_IOCTL_MAX_MSGS]i2cMsg)(unsafe.Pointer(d.msgs))[:d.nmsgs:d.nmsgs]
+	for i := range msgs {
+		m := &msgs[i]
+		c, err := a.target(m.addr)
+		if err != nil {
+			return err
+		}
+		buf := msgData(m)
+		op := fakeOp{rdwr: true, addr: m.addr, flags: m.flags}
+		switch {
+		case m.flags&I2C_M_RD != 0 && m.flags&I2C_M_RECV_LEN != 0:
+			// buf[0] holds number of bytes to read
+			// in addition to block: count byte and PEC.
+			extra := int(buf[0])
+			c.read(buf[:1])
+			n := int(buf[0]) + extra - 1
+			c.read(buf[1 : 1+n])
+			op.kind = "read"
+			op.data = buf[:1+n]
+		case m.flags&I2C_M_RD != 0:
+			c.read(buf)
+			op.kind = "read"
+			op.data = buf
+		default:
+			c.write(buf)
+			op.kind = "write"
+			op.data = buf
+		}
+		a.record(op)
+	}
+	return nil
+}
+
+func (f *fakeFile) smbus(d *i2cSmbusIoctlData) error {
+	a := f.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.record(fakeOp{kind: "smbus", addr: f.addr, cmd: uintptr(d.size),
+		arg: uintptr(d.command), flags: uint16(d.readWrite)})
+	c, err := a.target(f.addr)
+	if err != nil {
+		return err
+	}
+	switch {
+	case d.size == I2C_SMBUS_QUICK:
+	case d.size == I2C_SMBUS_BYTE && d.readWrite == I2C_SMBUS_WRITE:
+		c.write([]byte{d.command})
+	case d.size == I2C_SMBUS_BYTE:
+		c.read(d.data[:1])
+	case d.size == I2C_SMBUS_BLOCK_DATA && d.readWrite == I2C_SMBUS_WRITE:
+		c.write(append([]byte{d.command}, d.data[:d.data[0]+1]...))
+	case d.size == I2C_SMBUS_BLOCK_DATA:
+		c.write([]byte{d.command})
+		c.read(d.data[:1])
+		if d.data[0] > i2cSmbusBlockMax {
+			return syscall.EPROTO
+		}
+		c.read(d.data[1 : d.data[0]+1])
+	default:
+		return syscall.EOPNOTSUPP
+	}
+	return nil
+}
+
+// installFakeBuses make bus device files /dev/i2c-N backed by fake
+// adapters from buses for the duration of test.
+func installFakeBuses(t testing.TB, buses map[int]*fakeAdapter) {
+	prefix, open := busPrefix, openDevice
+	t.Cleanup(func() {
+		busPrefix, openDevice = prefix, open
+	})
+	busPrefix = filepath.Join(t.TempDir(), "i2c-")
+	paths := make(map[string]*fakeAdapter)
+	for bus, a := range buses {
+		path := busPath(bus)
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths[path] = a
+	}
+	openDevice = func(path string) (device, error) {
+		a, ok := paths[path]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: path, Err: syscall.ENOENT}
+		}
+		return a.open(), nil
+	}
+}
+
+// newFake open connection to chip at address addr on bus 1 of fake
+// adapter, with fake clock, then options opts applied.
+func newFake(t testing.TB, addr uint8, opts ...Option) (*I2C, *fakeAdapter, *fakeClock) {
+	a := newFakeAdapter()
+	a.chip(uint16(addr))
+	clock := newFakeClock()
+	a.clock = clock
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	v, err := NewI2C(addr, 1, append([]Option{WithClock(clock)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { v.Close() })
+	a.reset()
+	return v, a, clock
+}
+
+// quietLog suppress package log output for the duration of test.
+func quietLog(t testing.TB) {
+	_, level := lg.get()
+	SetQuiet()
+	t.Cleanup(func() { SetLogLevel(level) })
+}
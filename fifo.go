@@ -0,0 +1,33 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ReadFIFO drains FIFO buffer of I2C-device (IMUs like MPU-6050, for
+// instance): reads number of bytes available from countReg, as unsigned
+// value of countWidth bytes (1 or 2, big endian), then reads exactly
+// that many bytes from dataReg. Both reads are done under one connection
+// lock. Returns empty slice, if FIFO is empty.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadFIFO(countReg byte, dataReg byte, countWidth int) ([]byte, error) {
+	if countWidth != 1 && countWidth != 2 {
+		return nil, fmt.Errorf("FIFO count width %d not in 1, 2", countWidth)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	count, err := v.readRegUint(countReg, countWidth, binary.BigEndian)
+	if err != nil {
+		return nil, err
+	}
+	v.log.Debugf("FIFO count %d read from reg 0x%0X", count, countReg)
+	if count == 0 {
+		return []byte{}, nil
+	}
+	buf, _, err := v.readRegBytes(dataReg, int(count))
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
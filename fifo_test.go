@@ -0,0 +1,65 @@
+package i2c
+
+import (
+	"bytes"
+	"testing"
+)
+
+// fifoChip attach to fake adapter MPU-6050 like chip at addr, which
+// FIFO holds data: count is kept in registers 0x72..0x73 (big endian),
+// and read from data register 0x74 drains FIFO.
+func fifoChip(a *fakeAdapter, addr uint16, data []byte) *fakeChip {
+	c := a.chip(addr)
+	c.regs[0x72], c.regs[0x73] = byte(len(data)>>8), byte(len(data))
+	c.onRead = func(c *fakeChip) {
+		if c.ptr == 0x74 {
+			copy(c.regs[0x74:], data)
+			c.regs[0x72], c.regs[0x73] = 0, 0
+		}
+	}
+	return c
+}
+
+func TestReadFIFO(t *testing.T) {
+	v, a, _ := newFake(t, 0x68)
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	fifoChip(a, 0x68, data)
+	got, err := v.ReadFIFO(0x72, 0x74, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("FIFO read %d bytes [% X...], want %d", len(got), got[:8], len(data))
+	}
+	// Exactly count bytes are read from data register.
+	reads := a.operations("read")
+	if len(reads) != 2 || len(reads[0].data) != 2 || len(reads[1].data) != 300 {
+		t.Errorf("reads of %d operations, want count and 300 data bytes", len(reads))
+	}
+
+	// Drained FIFO gives empty result, without data read.
+	a.reset()
+	got, err = v.ReadFIFO(0x72, 0x74, 2)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("empty FIFO read = %v, %v, want empty slice", got, err)
+	}
+	if reads := a.operations("read"); len(reads) != 1 {
+		t.Errorf("%d reads from empty FIFO, want count only", len(reads))
+	}
+}
+
+func TestReadFIFOByteCount(t *testing.T) {
+	v, a, _ := newFake(t, 0x68)
+	// 1-byte count register holds low byte only.
+	fifoChip(a, 0x68, []byte{0x01, 0x02, 0x03})
+	got, err := v.ReadFIFO(0x73, 0x74, 1)
+	if err != nil || !bytes.Equal(got, []byte{0x01, 0x02, 0x03}) {
+		t.Errorf("ReadFIFO = [% X], %v", got, err)
+	}
+	if _, err := v.ReadFIFO(0x72, 0x74, 4); err == nil {
+		t.Error("count width 4 accepted")
+	}
+}
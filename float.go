@@ -0,0 +1,94 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ReadRegFloat32BE reads IEEE-754 single precision float (32 bits),
+// stored in big endian order, from I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegFloat32BE(reg byte) (float32, error) {
+	u, err := v.readRegFloat(reg, 4, binary.BigEndian)
+	return math.Float32frombits(uint32(u)), err
+}
+
+// ReadRegFloat32LE reads IEEE-754 single precision float (32 bits),
+// stored in little endian order, from I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegFloat32LE(reg byte) (float32, error) {
+	u, err := v.readRegFloat(reg, 4, binary.LittleEndian)
+	return math.Float32frombits(uint32(u)), err
+}
+
+// readRegFloat reads bit pattern of IEEE-754 float of width bytes
+// (4 or 8) stored with byte order specified in order.
+func (v *I2C) readRegFloat(reg byte, width int, order binary.ByteOrder) (uint64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.readRegUint(reg, width, order)
+}
+
+// ReadRegFloat64BE reads IEEE-754 double precision float (64 bits),
+// stored in big endian order, from I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegFloat64BE(reg byte) (float64, error) {
+	u, err := v.readRegFloat(reg, 8, binary.BigEndian)
+	return math.Float64frombits(u), err
+}
+
+// ReadRegFloat64LE reads IEEE-754 double precision float (64 bits),
+// stored in little endian order, from I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegFloat64LE(reg byte) (float64, error) {
+	u, err := v.readRegFloat(reg, 8, binary.LittleEndian)
+	return math.Float64frombits(u), err
+}
+
+// WriteRegFloat32BE writes IEEE-754 single precision float (32 bits)
+// value in big endian order to I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegFloat32BE(reg byte, value float32) error {
+	return v.writeRegFloat(reg, 4, uint64(math.Float32bits(value)), binary.BigEndian)
+}
+
+// WriteRegFloat32LE writes IEEE-754 single precision float (32 bits)
+// value in little endian order to I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegFloat32LE(reg byte, value float32) error {
+	return v.writeRegFloat(reg, 4, uint64(math.Float32bits(value)), binary.LittleEndian)
+}
+
+// writeRegFloat writes bit pattern u of IEEE-754 float of width
+// bytes (4 or 8) with byte order specified in order.
+func (v *I2C) writeRegFloat(reg byte, width int, u uint64, order binary.ByteOrder) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeRegUint(reg, width, u, order); err != nil {
+		return err
+	}
+	v.log.Debugf("Write F%d 0x%0*X (%v) to reg 0x%0X", width*8, width*2, u, order, reg)
+	return nil
+}
+
+// WriteRegFloat64BE writes IEEE-754 double precision float (64 bits)
+// value in big endian order to I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegFloat64BE(reg byte, value float64) error {
+	return v.writeRegFloat(reg, 8, math.Float64bits(value), binary.BigEndian)
+}
+
+// WriteRegFloat64LE writes IEEE-754 double precision float (64 bits)
+// value in little endian order to I2C-device starting from address
+// specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegFloat64LE(reg byte, value float64) error {
+	return v.writeRegFloat(reg, 8, math.Float64bits(value), binary.LittleEndian)
+}
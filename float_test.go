@@ -0,0 +1,70 @@
+package i2c
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestRegFloat32(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	values := []float32{0, 1.5, -273.15, float32(math.Inf(1)),
+		float32(math.Copysign(0, -1)), float32(math.NaN())}
+	for _, value := range values {
+		if err := v.WriteRegFloat32BE(0x10, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.WriteRegFloat32LE(0x20, value); err != nil {
+			t.Fatal(err)
+		}
+		bits := math.Float32bits(value)
+		be := []byte{byte(bits >> 24), byte(bits >> 16), byte(bits >> 8), byte(bits)}
+		le := []byte{be[3], be[2], be[1], be[0]}
+		if !bytes.Equal(c.regs[0x10:0x14], be) || !bytes.Equal(c.regs[0x20:0x24], le) {
+			t.Errorf("%v stored as [% X] BE, [% X] LE", value, c.regs[0x10:0x14], c.regs[0x20:0x24])
+		}
+		gotBE, err := v.ReadRegFloat32BE(0x10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotLE, err := v.ReadRegFloat32LE(0x20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compare bit patterns: NaN != NaN, and 0 == -0.
+		if math.Float32bits(gotBE) != bits || math.Float32bits(gotLE) != bits {
+			t.Errorf("%v read back as %v BE, %v LE", value, gotBE, gotLE)
+		}
+	}
+}
+
+func TestRegFloat64(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	values := []float64{0, math.Pi, -1e-300, math.Inf(-1),
+		math.Copysign(0, -1), math.NaN()}
+	for _, value := range values {
+		if err := v.WriteRegFloat64BE(0x10, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.WriteRegFloat64LE(0x20, value); err != nil {
+			t.Fatal(err)
+		}
+		bits := math.Float64bits(value)
+		if c.regs[0x10] != byte(bits>>56) || c.regs[0x20] != byte(bits) {
+			t.Errorf("%v stored as [% X] BE, [% X] LE", value, c.regs[0x10:0x18], c.regs[0x20:0x28])
+		}
+		gotBE, err := v.ReadRegFloat64BE(0x10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotLE, err := v.ReadRegFloat64LE(0x20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Float64bits(gotBE) != bits || math.Float64bits(gotLE) != bits {
+			t.Errorf("%v read back as %v BE, %v LE", value, gotBE, gotLE)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package i2c
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// funcNames map I2C adapter functionality flags to its names.
+var funcNames = []struct {
+	flag uint64
+	name string
+}{
+	{I2C_FUNC_I2C, "I2C_FUNC_I2C"},
+	{I2C_FUNC_10BIT_ADDR, "I2C_FUNC_10BIT_ADDR"},
+	{I2C_FUNC_PROTOCOL_MANGLING, "I2C_FUNC_PROTOCOL_MANGLING"},
+	{I2C_FUNC_SMBUS_PEC, "I2C_FUNC_SMBUS_PEC"},
+	{I2C_FUNC_NOSTART, "I2C_FUNC_NOSTART"},
+	{I2C_FUNC_SLAVE, "I2C_FUNC_SLAVE"},
+	{I2C_FUNC_SMBUS_BLOCK_PROC_CALL, "I2C_FUNC_SMBUS_BLOCK_PROC_CALL"},
+	{I2C_FUNC_SMBUS_QUICK, "I2C_FUNC_SMBUS_QUICK"},
+	{I2C_FUNC_SMBUS_READ_BYTE, "I2C_FUNC_SMBUS_READ_BYTE"},
+	{I2C_FUNC_SMBUS_WRITE_BYTE, "I2C_FUNC_SMBUS_WRITE_BYTE"},
+	{I2C_FUNC_SMBUS_READ_BYTE_DATA, "I2C_FUNC_SMBUS_READ_BYTE_DATA"},
+	{I2C_FUNC_SMBUS_WRITE_BYTE_DATA, "I2C_FUNC_SMBUS_WRITE_BYTE_DATA"},
+	{I2C_FUNC_SMBUS_READ_WORD_DATA, "I2C_FUNC_SMBUS_READ_WORD_DATA"},
+	{I2C_FUNC_SMBUS_WRITE_WORD_DATA, "I2C_FUNC_SMBUS_WRITE_WORD_DATA"},
+	{I2C_FUNC_SMBUS_PROC_CALL, "I2C_FUNC_SMBUS_PROC_CALL"},
+	{I2C_FUNC_SMBUS_READ_BLOCK_DATA, "I2C_FUNC_SMBUS_READ_BLOCK_DATA"},
+	{I2C_FUNC_SMBUS_WRITE_BLOCK_DATA, "I2C_FUNC_SMBUS_WRITE_BLOCK_DATA"},
+	{I2C_FUNC_SMBUS_READ_I2C_BLOCK, "I2C_FUNC_SMBUS_READ_I2C_BLOCK"},
+	{I2C_FUNC_SMBUS_WRITE_I2C_BLOCK, "I2C_FUNC_SMBUS_WRITE_I2C_BLOCK"},
+	{I2C_FUNC_SMBUS_HOST_NOTIFY, "I2C_FUNC_SMBUS_HOST_NOTIFY"},
+}
+
+// funcFlagNames return names of flags set in mask, separated by "|".
+// Unknown bits are shown in hex.
+func funcFlagNames(mask uint64) string {
+	var names []string
+	for _, item := range funcNames {
+		if mask&item.flag != 0 {
+			names = append(names, item.name)
+			mask &^= item.flag
+		}
+	}
+	if mask != 0 {
+		names = append(names, fmt.Sprintf("0x%08X", mask))
+	}
+	return strings.Join(names, "|")
+}
+
+// checkFuncs verify that functionality mask funcs contains
+// all flags from required, otherwise return ErrUnsupportedFunc
+// naming missing flags.
+func checkFuncs(funcs, required uint64) error {
+	missing := required &^ funcs
+	if missing != 0 {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFunc, funcFlagNames(missing))
+	}
+	return nil
+}
+
+// queryFuncs query functionality mask of I2C adapter
+// opened as device d with I2C_FUNCS ioctl.
+func queryFuncs(d device) (uint32, error) {
+	// Kernel returns functionality as unsigned long.
+	var funcs uintptr
+	if err := devIoctlPtr(d, I2C_FUNCS, unsafe.Pointer(&funcs)); err != nil {
+		return 0, err
+	}
+	return uint32(funcs), nil
+}
+
+// Functions query I2C adapter functionality mask with I2C_FUNCS
+// ioctl: combination of I2C_FUNC_... constants. Lets code fall back
+// to plain read/write transfers, when adapter doesn't support
+// combined transactions or SMBus commands.
+func (v *I2C) Functions() (uint32, error) {
+	return queryFuncs(v.rc)
+}
+
+// Supports return true, if I2C adapter provide all functionality
+// specified in flags (combination of I2C_FUNC_... constants).
+func (v *I2C) Supports(flags uint32) (bool, error) {
+	funcs, err := v.Functions()
+	if err != nil {
+		return false, err
+	}
+	return funcs&flags == flags, nil
+}
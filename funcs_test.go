@@ -0,0 +1,62 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithRequiredFuncs(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x50)
+	a.funcs = I2C_FUNC_I2C | I2C_FUNC_SMBUS_QUICK
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+
+	v, err := NewI2C(0x50, 1, WithRequiredFuncs(I2C_FUNC_I2C))
+	if err != nil {
+		t.Fatalf("supported functionality rejected: %v", err)
+	}
+	v.Close()
+
+	_, err = NewI2C(0x50, 1,
+		WithRequiredFuncs(I2C_FUNC_I2C|I2C_FUNC_SMBUS_READ_BLOCK_DATA|I2C_FUNC_SMBUS_PEC))
+	if !errors.Is(err, ErrUnsupportedFunc) {
+		t.Fatalf("expected ErrUnsupportedFunc, got %v", err)
+	}
+	msg := err.Error()
+	for _, name := range []string{"I2C_FUNC_SMBUS_READ_BLOCK_DATA", "I2C_FUNC_SMBUS_PEC"} {
+		if !strings.Contains(msg, name) {
+			t.Errorf("missing flag %s not named in %q", name, msg)
+		}
+	}
+	if strings.Contains(msg, "I2C_FUNC_I2C") {
+		t.Errorf("supported flag named in %q", msg)
+	}
+}
+
+func TestFuncFlagNames(t *testing.T) {
+	got := funcFlagNames(I2C_FUNC_I2C | I2C_FUNC_SMBUS_QUICK | 1<<40)
+	want := "I2C_FUNC_I2C|I2C_FUNC_SMBUS_QUICK|0x10000000000"
+	if got != want {
+		t.Errorf("funcFlagNames = %q, want %q", got, want)
+	}
+}
+
+func TestFuncsIoctl(t *testing.T) {
+	// Value from linux/i2c-dev.h.
+	if I2C_FUNCS != 0x0705 {
+		t.Errorf("I2C_FUNCS = 0x%04X, want 0x0705", I2C_FUNCS)
+	}
+	v, a, _ := newFake(t, 0x50)
+	a.funcs = I2C_FUNC_I2C | I2C_FUNC_10BIT_ADDR
+	funcs, err := v.Functions()
+	if err != nil || funcs != I2C_FUNC_I2C|I2C_FUNC_10BIT_ADDR {
+		t.Errorf("Functions = 0x%08X, %v", funcs, err)
+	}
+	if ok, err := v.Supports(I2C_FUNC_I2C | I2C_FUNC_10BIT_ADDR); err != nil || !ok {
+		t.Errorf("supported functionality: %v, %v", ok, err)
+	}
+	if ok, err := v.Supports(I2C_FUNC_I2C | I2C_FUNC_SMBUS_READ_BLOCK_DATA); err != nil || ok {
+		t.Errorf("unsupported functionality: %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,24 @@
+// Package gologger route log output of i2c package to go-logger
+// (github.com/d2r2/go-logger) package logger "i2c", as i2c package
+// did by default before its Logger became pluggable. Import it only,
+// if you want former console output: i2c package itself doesn't
+// depend on go-logger and logs nothing by default.
+package gologger
+
+import (
+	i2c "github.com/d2r2/go-i2c"
+	logger "github.com/d2r2/go-logger"
+)
+
+// New create go-logger package logger "i2c" with verbosity
+// specified in level, suitable for i2c.SetLogger.
+// Verbosity may be changed later with logger.ChangePackageLogLevel.
+func New(level logger.LogLevel) i2c.Logger {
+	return logger.NewPackageLogger("i2c", level)
+}
+
+// Use route i2c package log output to go-logger package logger "i2c"
+// with verbosity specified in level (logger.DebugLevel was default).
+func Use(level logger.LogLevel) {
+	i2c.SetLogger(New(level))
+}
@@ -0,0 +1,11 @@
+package gologger_test
+
+import (
+	"github.com/d2r2/go-i2c/gologger"
+	logger "github.com/d2r2/go-logger"
+)
+
+func ExampleUse() {
+	// Restore former console output of i2c package at info level.
+	gologger.Use(logger.InfoLevel)
+}
@@ -10,17 +10,85 @@
 package i2c
 
 import (
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"sync"
 	"syscall"
+	"time"
+	"unsafe"
 )
 
 // I2C represents a connection to I2C-device.
+// It is safe for concurrent use: register helpers hold connection
+// lock from register address write till the end of data read,
+// so goroutines reading different registers can't interleave.
 type I2C struct {
-	addr uint8
-	bus  int
-	rc   *os.File
+	// Mutex to serialize multi-step register operations.
+	// Compound operation must hold it for entire duration (use
+	// unexported helpers, which don't lock, inside), as well as any
+	// operation changing connection state (address, descriptor),
+	// so state can't change in the middle of compound operation.
+	mu    sync.Mutex
+	addr  uint8
+	bus   int
+	rc    device
+	clock Clock
+	// buffer reused by block reads
+	blockBuf []byte
+	// aggregate bandwidth limit of Bus connection, nil if disabled
+	limit *tokenBucket
+	// buffer reused by register address write of register read
+	regBuf [1]byte
+	// kernel messages and ioctl argument reused by combined
+	// register reads
+	combMsgs [2]i2cMsg
+	combData i2cRdwrIoctlData
+	// automatic reconnect backoff, nil if disabled
+	reconnect *backoff
+	// values allowed to be written to registers
+	allowed map[byte][]byte
+	// write audit, nil if disabled
+	audit *writeAudit
+	// delay between write and read phases of register read
+	turnaround time.Duration
+	// the most recent read and write errors
+	lastErr lastErrors
+	// adapter functionality required at construction
+	requiredFuncs uint64
+	// dry-run mode: writes discarded, reads return pattern
+	dryRun     bool
+	dryPattern []byte
+	// delays to settle after register writes
+	settle map[byte]time.Duration
+	// minimum interval between reads
+	readPace pace
+	// automatic wake-up after idle, nil if disabled
+	wake *autoWake
+	// register reads done as combined I2C_RDWR transactions
+	combined bool
+	// byte order of 16-bit register addresses, nil for big-endian
+	regAddrOrder binary.ByteOrder
+	// 10-bit addressing mode and full 10-bit address
+	tenBit bool
+	addr10 uint16
+	// SMBus packet error checking enabled in kernel
+	pec bool
+	// log output of connection
+	log connLogger
+	// retry policy for transient transfer failures
+	retry retryPolicy
+	// bind address even if claimed by kernel driver
+	force bool
+	// device file path, empty for /dev/i2c-N
+	path string
+	// timeout of waiting for device acknowledge
+	// between chunk writes, zero if disabled
+	ackPoll time.Duration
 }
 
 // NewI2C opens a connection for I2C-device.
@@ -28,208 +96,842 @@ type I2C struct {
 // supported as well: you should preliminary specify
 // register address to read from, either write register
 // together with the data in case of write operations.
-func NewI2C(addr uint8, bus int) (*I2C, error) {
-	f, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0600)
+// Optional opts configure connection, see Option.
+func NewI2C(addr uint8, bus int, opts ...Option) (*I2C, error) {
+	v := &I2C{bus: bus, addr: addr, clock: systemClock{}}
+	return v.open(opts)
+}
+
+// NewTenBit opens a connection for I2C-device with 10-bit
+// address addr (0x000..0x3FF), like NewI2C does for 7-bit ones.
+// Adapter must support I2C_FUNC_10BIT_ADDR functionality.
+func NewTenBit(addr uint16, bus int, opts ...Option) (*I2C, error) {
+	if addr > 0x3FF {
+		return nil, fmt.Errorf("address 0x%0X out of 10-bit range", addr)
+	}
+	v := &I2C{bus: bus, addr: uint8(addr), tenBit: true, addr10: addr,
+		clock: systemClock{}}
+	return v.open(opts)
+}
+
+// NewForce opens a connection for I2C-device, like NewI2C does, but
+// binds address with I2C_SLAVE_FORCE, even if it's claimed by kernel
+// driver (like "i2cset -f" does). It's dangerous: driver and
+// application talk to device concurrently and may confuse it
+// or each other, so use it for debugging only.
+func NewForce(addr uint8, bus int, opts ...Option) (*I2C, error) {
+	v := &I2C{bus: bus, addr: addr, force: true, clock: systemClock{}}
+	return v.open(opts)
+}
+
+// open apply options opts and open device file
+// of I2C-connection prepared by constructor.
+func (v *I2C) open(opts []Option) (*I2C, error) {
+	if err := v.apply(opts); err != nil {
+		return nil, err
+	}
+	if v.dryRun {
+		v.log.Infof("Dry-run mode active on bus %d, address 0x%0X: "+
+			"no I2C-device is accessed", v.bus, v.GetAddr16())
+		return v, nil
+	}
+	f, err := openDevice(v.devPath())
 	if err != nil {
 		return nil, err
 	}
-	if err := ioctl(f.Fd(), I2C_SLAVE, uintptr(addr)); err != nil {
+	if err := v.attach(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// apply options opts to I2C-connection in order.
+func (v *I2C) apply(opts []Option) error {
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attach bind device file f to I2C-connection and verify
+// adapter functionality required by options.
+func (v *I2C) attach(f device) error {
+	if err := v.bind(f); err != nil {
+		return err
+	}
+	v.rc = f
+	if v.requiredFuncs != 0 {
+		funcs, err := v.Functions()
+		if err == nil {
+			err = checkFuncs(uint64(funcs), v.requiredFuncs)
+		}
+		if err != nil {
+			v.rc = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// NewAtPath opens a connection for I2C-device at address addr, like
+// NewI2C does, but with bus device file specified by path, for
+// non-standard /dev layouts, chroots and containers with bind-mounted
+// device nodes. Bus number is parsed from trailing digits of path,
+// otherwise it's -1; use GetPath to get path itself.
+func NewAtPath(path string, addr uint8, opts ...Option) (*I2C, error) {
+	v := &I2C{bus: busFromPath(path), addr: addr, path: path, clock: systemClock{}}
+	return v.open(opts)
+}
+
+// busFromPath parse bus number from trailing digits
+// of device file path, returning -1 if there are none.
+func busFromPath(path string) int {
+	i := len(path)
+	for i > 0 && path[i-1] >= '0' && path[i-1] <= '9' {
+		i--
+	}
+	bus, err := strconv.Atoi(path[i:])
+	if err != nil {
+		return -1
+	}
+	return bus
+}
+
+// devPath return path of bus device file.
+func (v *I2C) devPath() string {
+	if v.path != "" {
+		return v.path
+	}
+	return busPath(v.bus)
+}
+
+// GetPath return path of bus device file.
+func (v *I2C) GetPath() string {
+	return v.devPath()
+}
+
+// NewFromFile creates connection for I2C-device at address addr
+// on already open device file f (shared with other library, for
+// instance), instead of opening /dev/i2c-N itself. Bus number is
+// parsed from trailing digits of file name (like /dev/i2c-N),
+// otherwise it's -1. Close closes f. Files which don't support ioctl calls
+// (pipes, regular files) are accepted as is, what lets register
+// helpers run against canned data without hardware, unless options
+// need ioctl calls to take effect (WithPEC, WithRequiredFuncs).
+func NewFromFile(f *os.File, addr uint8, opts ...Option) (*I2C, error) {
+	v := &I2C{bus: busFromPath(f.Name()), addr: addr, path: f.Name(),
+		clock: systemClock{}}
+	if err := v.apply(opts); err != nil {
 		return nil, err
 	}
-	v := &I2C{rc: f, bus: bus, addr: addr}
+	if err := v.attach(f); err != nil {
+		if !errors.Is(err, syscall.ENOTTY) || v.needsIoctl() {
+			return nil, err
+		}
+		v.log.Debugf("File %s doesn't support I2C ioctl calls: %v", f.Name(), err)
+		v.rc = f
+	}
 	return v, nil
 }
 
+// needsIoctl return true, if connection settings can't take effect
+// without ioctl calls, other than binding slave address.
+func (v *I2C) needsIoctl() bool {
+	return v.tenBit || v.pec || v.force || v.requiredFuncs != 0
+}
+
 // GetBus return bus line, where I2C-device is allocated.
 func (v *I2C) GetBus() int {
 	return v.bus
 }
 
 // GetAddr return device occupied address in the bus.
+// For 10-bit address use GetAddr16, since GetAddr
+// return its lower 8 bits only.
 func (v *I2C) GetAddr() uint8 {
 	return v.addr
 }
 
+// GetAddr16 return device occupied address in the bus,
+// either 7-bit or 10-bit one, without truncation.
+func (v *I2C) GetAddr16() uint16 {
+	if v.tenBit {
+		return v.addr10
+	}
+	return uint16(v.addr)
+}
+
+// slaveCmd return ioctl command to set slave address.
+func (v *I2C) slaveCmd() uintptr {
+	if v.force {
+		return I2C_SLAVE_FORCE
+	}
+	return I2C_SLAVE
+}
+
+// bind switch device file f to addressing mode
+// and slave address of I2C-connection.
+func (v *I2C) bind(f device) error {
+	if v.tenBit {
+		if err := devIoctl(f, I2C_TENBIT, 1); err != nil {
+			return err
+		}
+	}
+	if v.pec {
+		if err := devIoctl(f, I2C_PEC, 1); err != nil {
+			return err
+		}
+	}
+	return devIoctl(f, v.slaveCmd(), uintptr(v.GetAddr16()))
+}
+
+// SetAddr switch connection to another 7-bit slave address on the
+// same bus without reopening device file, which is cheap way to talk
+// to several devices (behind TCA9548A-like multiplexer, for instance).
+// Switch is serialized with register helpers by connection lock, but
+// other goroutines may switch address again between SetAddr and next
+// transfer: use WithAddr or Bus.ReadMany for atomic multi-device
+// sequences. Not supported for connections in 10-bit addressing mode.
+func (v *I2C) SetAddr(addr uint8) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.setAddr(addr); err != nil {
+		return v.opError("set address", err)
+	}
+	v.log.Debugf("Switched to address 0x%0X on bus %d", addr, v.bus)
+	return nil
+}
+
+// WithAddr switch connection to 7-bit slave address addr, call fn and
+// switch back to previous address, all under one connection lock, so
+// other goroutines can neither retarget connection nor interleave
+// transfers in between. Since lock is held, fn must use raw transfers
+// (WriteBytes, ReadBytes) only: register helpers would deadlock.
+// Returns error of fn, if any. Not supported for connections
+// in 10-bit addressing mode.
+func (v *I2C) WithAddr(addr uint8, fn func() error) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	prev := v.addr
+	if err := v.setAddr(addr); err != nil {
+		return v.opError("set address", err)
+	}
+	err := fn()
+	if rerr := v.setAddr(prev); rerr != nil && err == nil {
+		err = v.opError("set address", rerr)
+	}
+	return err
+}
+
+// setAddr switch connection to another 7-bit slave address.
+// Connection lock must be held.
+func (v *I2C) setAddr(addr uint8) error {
+	if v.tenBit {
+		return fmt.Errorf("can't switch connection with 10-bit address 0x%03X "+
+			"to 7-bit address 0x%02X", v.addr10, addr)
+	}
+	if !v.dryRun {
+		if err := devIoctl(v.rc, v.slaveCmd(), uintptr(addr)); err != nil {
+			return err
+		}
+	}
+	v.addr = addr
+	return nil
+}
+
 func (v *I2C) write(buf []byte) (int, error) {
-	return v.rc.Write(buf)
+	if v.dryRun {
+		v.log.Debugf("Dry-run: discard %d bytes write", len(buf))
+		return len(buf), nil
+	}
+	v.pace(len(buf))
+	v.wakeIfIdle()
+	n, err := v.rc.Write(buf)
+	if err != nil && v.tryReconnect(err) {
+		n, err = v.rc.Write(buf)
+	}
+	for i := 0; err != nil && v.retry.allow(i, err); i++ {
+		v.log.Debugf("Write failed: %v, retry %d of %d", err, i+1, v.retry.count)
+		v.clock.Sleep(v.retry.delay)
+		n, err = v.rc.Write(buf)
+	}
+	if err != nil {
+		v.setWriteError(err)
+		return n, v.opError("write", err)
+	}
+	return n, nil
 }
 
 // WriteBytes send bytes to the remote I2C-device. The interpretation of
 // the message is implementation-dependent.
+// WriteBytes is a raw single transfer, which doesn't acquire
+// connection lock: use Lock/Unlock around sequence of raw transfers.
 func (v *I2C) WriteBytes(buf []byte) (int, error) {
-	lg.Debugf("Write %d hex bytes: [%+v]", len(buf), hex.EncodeToString(buf))
+	return v.writeBytes(nextTx(), buf)
+}
+
+// writeBytes is WriteBytes, logged as a part of transaction tx.
+func (v *I2C) writeBytes(tx uint64, buf []byte) (int, error) {
+	n, err := v.writeBytes16(tx, buf)
+	if err != nil {
+		return n, err
+	}
+	// Register address write alone doesn't change register.
+	if len(buf) > 1 {
+		if d, ok := v.settle[buf[0]]; ok {
+			v.clock.Sleep(d)
+		}
+	}
+	return n, nil
+}
+
+// writeBytes16 is writeBytes for data prefixed with 16-bit register
+// address: settle delays, keyed on 8-bit register address, don't apply.
+func (v *I2C) writeBytes16(tx uint64, buf []byte) (int, error) {
+	n, err := v.sendBytes(tx, buf)
+	if err != nil {
+		return n, err
+	}
+	v.auditWrite(buf)
+	return n, nil
+}
+
+// sendBytes write buf to I2C-device as a part of transaction tx,
+// bypassing write audit and settle delays, which apply to data writes
+// only: use it for register address write of register read.
+func (v *I2C) sendBytes(tx uint64, buf []byte) (int, error) {
+	if v.log.enabled(DebugLevel) {
+		v.log.Debugf("[tx %d] Write %d hex bytes: [%+v]", tx, len(buf), hex.EncodeToString(buf))
+	}
 	return v.write(buf)
 }
 
 func (v *I2C) read(buf []byte) (int, error) {
-	return v.rc.Read(buf)
+	if v.dryRun {
+		for i := range buf {
+			if len(v.dryPattern) > 0 {
+				buf[i] = v.dryPattern[i%len(v.dryPattern)]
+			} else {
+				buf[i] = 0
+			}
+		}
+		return len(buf), nil
+	}
+	v.pace(len(buf))
+	return v.doRead(func() (int, error) {
+		return v.rc.Read(buf)
+	})
+}
+
+// pace wait until n bytes may be transferred within rate limit
+// of Bus connection (see Bus.SetRateLimit), if any.
+func (v *I2C) pace(n int) {
+	if v.limit != nil {
+		v.limit.take(v.clock, n)
+	}
+}
+
+// doRead run read transfer fn with auto wake-up, minimum read
+// interval, reconnect and retries applied, as configured.
+// Shared by separate and combined (I2C_RDWR) reads.
+func (v *I2C) doRead(fn func() (int, error)) (int, error) {
+	v.wakeIfIdle()
+	v.readPace.wait(v.clock)
+	attempt := func() (int, error) {
+		n, err := fn()
+		v.readPace.mark(v.clock)
+		return n, err
+	}
+	n, err := attempt()
+	if err != nil && v.tryReconnect(err) {
+		n, err = attempt()
+	}
+	for i := 0; err != nil && v.retry.allow(i, err); i++ {
+		v.log.Debugf("Read failed: %v, retry %d of %d", err, i+1, v.retry.count)
+		v.clock.Sleep(v.retry.delay)
+		n, err = attempt()
+	}
+	if err != nil {
+		v.setReadError(err)
+		return n, v.opError("read", err)
+	}
+	return n, nil
 }
 
 // ReadBytes read bytes from I2C-device.
 // Number of bytes read correspond to buf parameter length.
+// ReadBytes is a raw single transfer, which doesn't acquire
+// connection lock: use Lock/Unlock around sequence of raw transfers.
 func (v *I2C) ReadBytes(buf []byte) (int, error) {
+	return v.readBytes(nextTx(), buf)
+}
+
+// readBytes is ReadBytes, logged as a part of transaction tx.
+func (v *I2C) readBytes(tx uint64, buf []byte) (int, error) {
 	n, err := v.read(buf)
 	if err != nil {
 		return n, err
 	}
-	lg.Debugf("Read %d hex bytes: [%+v]", len(buf), hex.EncodeToString(buf))
+	if v.log.enabled(DebugLevel) {
+		v.log.Debugf("[tx %d] Read %d hex bytes: [%+v]", tx, len(buf), hex.EncodeToString(buf))
+	}
 	return n, nil
 }
 
+// Lock acquire connection lock, to perform custom multi-step sequence
+// of raw WriteBytes and ReadBytes transfers, which other goroutines
+// can't interleave. All register helpers (ReadReg..., WriteReg...)
+// acquire the same lock for their whole duration, so they can't be
+// called, while lock is held: lock is not re-entrant and would deadlock.
+func (v *I2C) Lock() {
+	v.mu.Lock()
+}
+
+// Unlock release connection lock, acquired by Lock.
+func (v *I2C) Unlock() {
+	v.mu.Unlock()
+}
+
 // Close I2C-connection.
 func (v *I2C) Close() error {
+	if v.dryRun {
+		return nil
+	}
 	return v.rc.Close()
 }
 
+// useCombined return true, if register reads are done as combined
+// transactions. Turnaround delay can't be inserted between phases
+// of combined transaction, so it make reads separate.
+func (v *I2C) useCombined() bool {
+	return v.combined && !v.dryRun && v.turnaround <= 0
+}
+
+// selectReg writes register address to I2C-device before read phase
+// of register read, followed by turnaround delay, if configured.
+func (v *I2C) selectReg(tx uint64, reg byte) error {
+	v.regBuf[0] = reg
+	_, err := v.sendBytes(tx, v.regBuf[:])
+	if err != nil {
+		return err
+	}
+	if v.turnaround > 0 {
+		v.clock.Sleep(v.turnaround)
+	}
+	return nil
+}
+
+// readReg reads len(buf) bytes from I2C-device starting from reg
+// address: either as one combined transaction, if enabled with
+// WithCombinedReads, or as register address write followed
+// by separate read.
+func (v *I2C) readReg(tx uint64, reg byte, buf []byte) (int, error) {
+	if v.useCombined() {
+		n, err := v.readRegRDWR(tx, reg, buf)
+		if err != nil {
+			return n, v.regError("read", reg, err)
+		}
+		return n, nil
+	}
+	err := v.selectReg(tx, reg)
+	if err != nil {
+		return 0, v.regError("read", reg, err)
+	}
+	n, err := v.readBytes(tx, buf)
+	if err != nil {
+		return n, v.regError("read", reg, err)
+	}
+	return n, nil
+}
+
 // ReadRegBytes read count of n byte's sequence from I2C-device
 // starting from reg address.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) ReadRegBytes(reg byte, n int) ([]byte, int, error) {
-	lg.Debugf("Read %d bytes starting from reg 0x%0X...", n, reg)
-	_, err := v.WriteBytes([]byte{reg})
-	if err != nil {
-		return nil, 0, err
-	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.readRegBytes(reg, n)
+}
+
+func (v *I2C) readRegBytes(reg byte, n int) ([]byte, int, error) {
+	tx := nextTx()
+	v.log.Debugf("[tx %d] Read %d bytes starting from reg 0x%0X...", tx, n, reg)
 	buf := make([]byte, n)
-	c, err := v.ReadBytes(buf)
+	c, err := v.readReg(tx, reg, buf)
 	if err != nil {
 		return nil, 0, err
 	}
 	return buf, c, nil
+}
 
+// ReadRegInto reads len(buf) bytes from I2C-device starting from reg
+// address into caller-supplied buf, returning number of bytes read.
+// Unlike ReadRegBytes, it doesn't allocate result, so buffer can be
+// reused across calls in tight polling loops.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegInto(reg byte, buf []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	if v.log.enabled(DebugLevel) {
+		v.log.Debugf("[tx %d] Read %d bytes starting from reg 0x%0X...", tx, len(buf), reg)
+	}
+	return v.readReg(tx, reg, buf)
 }
 
-// ReadRegU8 reads byte from I2C-device register specified in reg.
+// WriteRegBytes writes data to I2C-device starting from reg address.
+// Register address and data are sent as one message (single STOP
+// at the end), what devices latching configuration on STOP require.
 // SMBus (System Management Bus) protocol over I2C.
-func (v *I2C) ReadRegU8(reg byte) (byte, error) {
-	_, err := v.WriteBytes([]byte{reg})
+func (v *I2C) WriteRegBytes(reg byte, data []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.writeRegBytes(reg, data)
+}
+
+// WriteRegBytesWait writes data to I2C-device starting from reg address,
+// like WriteRegBytes does, then waits for after, still holding connection
+// lock, so next transfer can't arrive before slow device (EEPROM in write
+// cycle, for instance) is ready.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegBytesWait(reg byte, data []byte, after time.Duration) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeRegBytes(reg, data); err != nil {
+		return err
+	}
+	v.clock.Sleep(after)
+	return nil
+}
+
+func (v *I2C) writeRegBytes(reg byte, data []byte) error {
+	buf := make([]byte, 0, len(data)+1)
+	buf = append(buf, reg)
+	buf = append(buf, data...)
+	tx := nextTx()
+	_, err := v.writeBytes(tx, buf)
 	if err != nil {
-		return 0, err
+		return v.regError("write", reg, err)
 	}
+	v.log.Debugf("[tx %d] Write %d bytes to reg 0x%0X", tx, len(data), reg)
+	return nil
+}
+
+// ReadRegU8 reads byte from I2C-device register specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU8(reg byte) (byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.readRegU8(reg)
+}
+
+func (v *I2C) readRegU8(reg byte) (byte, error) {
+	tx := nextTx()
 	buf := make([]byte, 1)
-	_, err = v.ReadBytes(buf)
+	_, err := v.readReg(tx, reg, buf)
 	if err != nil {
 		return 0, err
 	}
-	lg.Debugf("Read U8 %d from reg 0x%0X", buf[0], reg)
+	v.log.Debugf("[tx %d] Read U8 %d from reg 0x%0X", tx, buf[0], reg)
 	return buf[0], nil
 }
 
 // WriteRegU8 writes byte to I2C-device register specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) WriteRegU8(reg byte, value byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.writeRegU8(reg, value)
+}
+
+func (v *I2C) writeRegU8(reg byte, value byte) error {
+	if err := v.checkAllowed(reg, value); err != nil {
+		return err
+	}
 	buf := []byte{reg, value}
-	_, err := v.WriteBytes(buf)
+	tx := nextTx()
+	_, err := v.writeBytes(tx, buf)
 	if err != nil {
-		return err
+		return v.regError("write", reg, err)
 	}
-	lg.Debugf("Write U8 %d to reg 0x%0X", value, reg)
+	v.log.Debugf("[tx %d] Write U8 %d to reg 0x%0X", tx, value, reg)
 	return nil
 }
 
-// ReadRegU16BE reads unsigned big endian word (16 bits)
-// from I2C-device starting from address specified in reg.
+// ReadRegS8 reads signed byte from I2C-device register specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
-func (v *I2C) ReadRegU16BE(reg byte) (uint16, error) {
-	_, err := v.WriteBytes([]byte{reg})
+func (v *I2C) ReadRegS8(reg byte) (int8, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	buf := make([]byte, 1)
+	_, err := v.readReg(tx, reg, buf)
 	if err != nil {
 		return 0, err
 	}
-	buf := make([]byte, 2)
-	_, err = v.ReadBytes(buf)
+	b := int8(buf[0])
+	v.log.Debugf("[tx %d] Read S8 %d from reg 0x%0X", tx, b, reg)
+	return b, nil
+}
+
+// WriteRegS8 writes signed byte to I2C-device register specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegS8(reg byte, value int8) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.checkAllowed(reg, byte(value)); err != nil {
+		return err
+	}
+	buf := []byte{reg, byte(value)}
+	tx := nextTx()
+	_, err := v.writeBytes(tx, buf)
+	if err != nil {
+		return v.regError("write", reg, err)
+	}
+	v.log.Debugf("[tx %d] Write S8 %d to reg 0x%0X", tx, value, reg)
+	return nil
+}
+
+// ReadRegU16 reads unsigned word (16 bits) from I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU16(reg byte, order binary.ByteOrder) (uint16, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	u, err := v.readRegUint(reg, 2, order)
 	if err != nil {
 		return 0, err
 	}
-	w := uint16(buf[0])<<8 + uint16(buf[1])
-	lg.Debugf("Read U16 %d from reg 0x%0X", w, reg)
-	return w, nil
+	v.log.Debugf("Read U16 %d (%v) from reg 0x%0X", u, order, reg)
+	return uint16(u), nil
+}
+
+// ReadRegS16 reads signed word (16 bits) from I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegS16(reg byte, order binary.ByteOrder) (int16, error) {
+	w, err := v.ReadRegU16(reg, order)
+	return int16(w), err
+}
+
+// ReadRegU16BE reads unsigned big endian word (16 bits)
+// from I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU16BE(reg byte) (uint16, error) {
+	return v.ReadRegU16(reg, binary.BigEndian)
 }
 
 // ReadRegU16LE reads unsigned little endian word (16 bits)
 // from I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) ReadRegU16LE(reg byte) (uint16, error) {
-	w, err := v.ReadRegU16BE(reg)
-	if err != nil {
-		return 0, err
-	}
-	// exchange bytes
-	w = (w&0xFF)<<8 + w>>8
-	return w, nil
+	return v.ReadRegU16(reg, binary.LittleEndian)
 }
 
 // ReadRegS16BE reads signed big endian word (16 bits)
 // from I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) ReadRegS16BE(reg byte) (int16, error) {
-	_, err := v.WriteBytes([]byte{reg})
-	if err != nil {
-		return 0, err
-	}
-	buf := make([]byte, 2)
-	_, err = v.ReadBytes(buf)
-	if err != nil {
-		return 0, err
-	}
-	w := int16(buf[0])<<8 + int16(buf[1])
-	lg.Debugf("Read S16 %d from reg 0x%0X", w, reg)
-	return w, nil
+	return v.ReadRegS16(reg, binary.BigEndian)
 }
 
 // ReadRegS16LE reads signed little endian word (16 bits)
 // from I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) ReadRegS16LE(reg byte) (int16, error) {
-	w, err := v.ReadRegS16BE(reg)
-	if err != nil {
-		return 0, err
-	}
-	// exchange bytes
-	w = (w&0xFF)<<8 + w>>8
-	return w, nil
+	return v.ReadRegS16(reg, binary.LittleEndian)
+}
 
+// WriteRegU16 writes unsigned word (16 bits) value to I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegU16(reg byte, value uint16, order binary.ByteOrder) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeRegUint(reg, 2, uint64(value), order); err != nil {
+		return err
+	}
+	v.log.Debugf("Write U16 %d (%v) to reg 0x%0X", value, order, reg)
+	return nil
 }
 
 // WriteRegU16BE writes unsigned big endian word (16 bits)
 // value to I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) WriteRegU16BE(reg byte, value uint16) error {
-	buf := []byte{reg, byte((value & 0xFF00) >> 8), byte(value & 0xFF)}
-	_, err := v.WriteBytes(buf)
-	if err != nil {
-		return err
-	}
-	lg.Debugf("Write U16 %d to reg 0x%0X", value, reg)
-	return nil
+	return v.WriteRegU16(reg, value, binary.BigEndian)
 }
 
 // WriteRegU16LE writes unsigned little endian word (16 bits)
 // value to I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) WriteRegU16LE(reg byte, value uint16) error {
-	w := (value*0xFF00)>>8 + value<<8
-	return v.WriteRegU16BE(reg, w)
+	return v.WriteRegU16(reg, value, binary.LittleEndian)
 }
 
 // WriteRegS16BE writes signed big endian word (16 bits)
 // value to I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (v *I2C) WriteRegS16BE(reg byte, value int16) error {
-	buf := []byte{reg, byte((uint16(value) & 0xFF00) >> 8), byte(value & 0xFF)}
-	_, err := v.WriteBytes(buf)
+	return v.WriteRegU16(reg, uint16(value), binary.BigEndian)
+}
+
+// WriteRegS16LE writes signed little endian word (16 bits)
+// value to I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegS16LE(reg byte, value int16) error {
+	return v.WriteRegU16(reg, uint16(value), binary.LittleEndian)
+}
+
+// ReadRegU32 reads unsigned double word (32 bits) from I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU32(reg byte, order binary.ByteOrder) (uint32, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	u, err := v.readRegUint(reg, 4, order)
 	if err != nil {
+		return 0, err
+	}
+	v.log.Debugf("Read U32 %d (%v) from reg 0x%0X", u, order, reg)
+	return uint32(u), nil
+}
+
+// ReadRegS32 reads signed double word (32 bits) from I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegS32(reg byte, order binary.ByteOrder) (int32, error) {
+	w, err := v.ReadRegU32(reg, order)
+	return int32(w), err
+}
+
+// ReadRegU32BE reads unsigned big endian double word (32 bits)
+// from I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU32BE(reg byte) (uint32, error) {
+	return v.ReadRegU32(reg, binary.BigEndian)
+}
+
+// ReadRegU32LE reads unsigned little endian double word (32 bits)
+// from I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegU32LE(reg byte) (uint32, error) {
+	return v.ReadRegU32(reg, binary.LittleEndian)
+}
+
+// ReadRegS32BE reads signed big endian double word (32 bits)
+// from I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegS32BE(reg byte) (int32, error) {
+	return v.ReadRegS32(reg, binary.BigEndian)
+}
+
+// ReadRegS32LE reads signed little endian double word (32 bits)
+// from I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegS32LE(reg byte) (int32, error) {
+	return v.ReadRegS32(reg, binary.LittleEndian)
+}
+
+// WriteRegU32 writes unsigned double word (32 bits) value to I2C-device
+// starting from address specified in reg, with byte order
+// specified in order (binary.BigEndian or binary.LittleEndian).
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegU32(reg byte, value uint32, order binary.ByteOrder) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeRegUint(reg, 4, uint64(value), order); err != nil {
 		return err
 	}
-	lg.Debugf("Write S16 %d to reg 0x%0X", value, reg)
+	v.log.Debugf("Write U32 %d (%v) to reg 0x%0X", value, order, reg)
 	return nil
 }
 
-// WriteRegS16LE writes signed little endian word (16 bits)
+// WriteRegU32BE writes unsigned big endian double word (32 bits)
 // value to I2C-device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
-func (v *I2C) WriteRegS16LE(reg byte, value int16) error {
-	w := int16((uint16(value)*0xFF00)>>8) + value<<8
-	return v.WriteRegS16BE(reg, w)
+func (v *I2C) WriteRegU32BE(reg byte, value uint32) error {
+	return v.WriteRegU32(reg, value, binary.BigEndian)
+}
+
+// WriteRegU32LE writes unsigned little endian double word (32 bits)
+// value to I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegU32LE(reg byte, value uint32) error {
+	return v.WriteRegU32(reg, value, binary.LittleEndian)
+}
+
+// WriteRegS32BE writes signed big endian double word (32 bits)
+// value to I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegS32BE(reg byte, value int32) error {
+	return v.WriteRegU32(reg, uint32(value), binary.BigEndian)
+}
+
+// WriteRegS32LE writes signed little endian double word (32 bits)
+// value to I2C-device starting from address specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegS32LE(reg byte, value int32) error {
+	return v.WriteRegU32(reg, uint32(value), binary.LittleEndian)
+}
+
+// device is an open bus device file (*os.File normally).
+type device interface {
+	io.ReadWriteCloser
+	Fd() uintptr
+}
+
+// ioctlDevice is implemented by devices, which serve ioctl
+// calls themselves instead of kernel (test doubles).
+type ioctlDevice interface {
+	ioctl(cmd, arg uintptr) error
+	ioctlPtr(cmd uintptr, arg unsafe.Pointer) error
+}
+
+// busPrefix is a path prefix of bus device files,
+// followed by bus number.
+var busPrefix = "/dev/i2c-"
+
+// busPath return path of device file for bus.
+func busPath(bus int) string {
+	return busPrefix + strconv.Itoa(bus)
+}
+
+// openDevice open bus device file at path.
+var openDevice = func(path string) (device, error) {
+	return os.OpenFile(path, os.O_RDWR, 0600)
+}
+
+// devIoctl perform ioctl call on device d.
+// Connection in dry-run mode has no device file (d is nil),
+// so ErrDryRun is returned.
+func devIoctl(d device, cmd, arg uintptr) error {
+	if d == nil {
+		return ErrDryRun
+	}
+	if h, ok := d.(ioctlDevice); ok {
+		return h.ioctl(cmd, arg)
+	}
+	return ioctl(d.Fd(), cmd, arg)
+}
+
+// devIoctlPtr perform ioctl call with pointer argument on device d.
+func devIoctlPtr(d device, cmd uintptr, arg unsafe.Pointer) error {
+	if d == nil {
+		return ErrDryRun
+	}
+	if h, ok := d.(ioctlDevice); ok {
+		return h.ioctlPtr(cmd, arg)
+	}
+	return ioctlPtr(d.Fd(), cmd, arg)
 }
 
 func ioctl(fd, cmd, arg uintptr) error {
@@ -239,3 +941,15 @@ func ioctl(fd, cmd, arg uintptr) error {
 	}
 	return nil
 }
+
+// ioctlPtr is a variant of ioctl, which pass pointer to kernel
+// structure as an argument. Pointer is converted to uintptr
+// right in the syscall invocation, as unsafe.Pointer rules require,
+// so the structure remains valid until syscall returns.
+func ioctlPtr(fd, cmd uintptr, arg unsafe.Pointer) error {
+	_, _, err := syscall.Syscall6(syscall.SYS_IOCTL, fd, cmd, uintptr(arg), 0, 0, 0)
+	if err != 0 {
+		return err
+	}
+	return nil
+}
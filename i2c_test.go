@@ -0,0 +1,598 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSetAddrDoesNotInterleave(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x41)
+	quietLog(t)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if err := v.SetAddr(0x40 + uint8(i%2)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if _, err := v.ReadRegU16BE(0x10); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	// Every read must go to the device, which register
+	// address has been written to just before.
+	ops := a.operations("read", "write", "ioctl")
+	for i, op := range ops {
+		if op.kind != "read" {
+			continue
+		}
+		if i == 0 || ops[i-1].kind != "write" || ops[i-1].addr != op.addr {
+			t.Fatalf("read at 0x%02X not preceded by register write to it", op.addr)
+		}
+	}
+}
+
+func TestTurnaroundDelay(t *testing.T) {
+	v, a, clock := newFake(t, 0x40, WithTurnaroundDelay(2*time.Millisecond))
+	if _, err := v.ReadRegU16BE(0x10); err != nil {
+		t.Fatal(err)
+	}
+	ops := a.operations("read", "write")
+	if len(ops) != 2 || ops[0].kind != "write" || ops[1].kind != "read" {
+		t.Fatalf("unexpected operations %+v", ops)
+	}
+	if d := ops[1].at.Sub(ops[0].at); d != 2*time.Millisecond {
+		t.Errorf("delay between register write and read %v, want 2ms", d)
+	}
+	if slept := clock.Slept(); len(slept) != 1 {
+		t.Errorf("sleeps %v, want single turnaround delay", slept)
+	}
+}
+
+func TestConcurrentRegReads(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	quietLog(t)
+	const workers = 8
+	for i := 0; i < workers; i++ {
+		c.regs[2*i] = byte(i)
+		c.regs[2*i+1] = byte(0x80 + i)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			want := uint16(i)<<8 | uint16(0x80+i)
+			for j := 0; j < 100; j++ {
+				w, err := v.ReadRegU16BE(byte(2 * i))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if w != want {
+					t.Errorf("reg 0x%02X: read 0x%04X, want 0x%04X", 2*i, w, want)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestWriteReg16LE(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	tests := []struct {
+		value uint16
+		want  []byte
+	}{
+		{0x1234, []byte{0x05, 0x34, 0x12}},
+		{0x00FF, []byte{0x05, 0xFF, 0x00}},
+		{0xFF00, []byte{0x05, 0x00, 0xFF}},
+		{0x8001, []byte{0x05, 0x01, 0x80}},
+	}
+	for _, test := range tests {
+		a.reset()
+		if err := v.WriteRegU16LE(0x05, test.value); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.WriteRegS16LE(0x05, int16(test.value)); err != nil {
+			t.Fatal(err)
+		}
+		w := a.writes()
+		if len(w) != 2 || !bytes.Equal(w[0], test.want) || !bytes.Equal(w[1], test.want) {
+			t.Errorf("0x%04X written as %X, want [% X] twice", test.value, w, test.want)
+		}
+	}
+}
+
+func TestNewTenBit(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x3A5).regs[0x10] = 0x5A
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	v, err := NewTenBit(0x3A5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	ops := a.operations("ioctl")
+	tenBit := -1
+	for i, op := range ops {
+		if op.cmd == I2C_TENBIT && op.arg == 1 {
+			tenBit = i
+		}
+		if op.cmd == I2C_SLAVE && (tenBit < 0 || tenBit > i || op.arg != 0x3A5) {
+			t.Errorf("slave address 0x%X set before I2C_TENBIT: %+v", op.arg, ops)
+		}
+	}
+	if tenBit < 0 {
+		t.Fatalf("I2C_TENBIT not issued: %+v", ops)
+	}
+	if v.GetAddr16() != 0x3A5 {
+		t.Errorf("GetAddr16 = 0x%X, want 0x3A5", v.GetAddr16())
+	}
+	if b, err := v.ReadRegU8(0x10); err != nil || b != 0x5A {
+		t.Errorf("ReadRegU8 = 0x%02X, %v", b, err)
+	}
+
+	// Combined transactions carry I2C_M_TEN flag.
+	a.reset()
+	if _, err := v.WriteRead([]byte{0x10}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range a.operations("read", "write") {
+		if op.addr != 0x3A5 || op.flags&I2C_M_TEN == 0 {
+			t.Errorf("message %+v, want 10-bit address 0x3A5", op)
+		}
+	}
+
+	if _, err := NewTenBit(0x400, 1); err == nil {
+		t.Error("address out of 10-bit range accepted")
+	}
+}
+
+// openFIFO create named pipe and open it for both reading
+// and writing, so data written is read back in FIFO order.
+func openFIFO(t *testing.T) *os.File {
+	path := filepath.Join(t.TempDir(), "i2c-7")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestNewFromFilePipe(t *testing.T) {
+	f := openFIFO(t)
+	// Canned register bytes queued ahead of register address write.
+	if _, err := f.Write([]byte{0x12, 0x34}); err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewFromFile(f, 0x40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if v.GetBus() != 7 {
+		t.Errorf("GetBus = %d, want 7", v.GetBus())
+	}
+	w, err := v.ReadRegU16BE(0x10)
+	if err != nil || w != 0x1234 {
+		t.Fatalf("ReadRegU16BE = 0x%04X, %v", w, err)
+	}
+	// Register address written is the rest of pipe content.
+	buf := make([]byte, 1)
+	if _, err := f.Read(buf); err != nil || !bytes.Equal(buf, []byte{0x10}) {
+		t.Errorf("register address [% X], %v", buf, err)
+	}
+}
+
+func TestNewFromFileIoctlOptions(t *testing.T) {
+	for _, opt := range []Option{WithPEC(), WithRequiredFuncs(I2C_FUNC_I2C)} {
+		f := openFIFO(t)
+		_, err := NewFromFile(f, 0x40, opt)
+		if !errors.Is(err, syscall.ENOTTY) {
+			t.Errorf("expected ENOTTY, got %v", err)
+		}
+		f.Close()
+	}
+}
+
+func TestReg32(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	copy(c.regs[0x00:], []byte{0xFF, 0xFF, 0xFF, 0xFE})
+	copy(c.regs[0x04:], []byte{0x00, 0x00, 0x00, 0x80})
+	if s, err := v.ReadRegS32BE(0x00); err != nil || s != -2 {
+		t.Errorf("ReadRegS32BE = %d, %v", s, err)
+	}
+	if s, err := v.ReadRegS32LE(0x00); err != nil || s != -16777217 {
+		t.Errorf("ReadRegS32LE = %d, %v", s, err)
+	}
+	if u, err := v.ReadRegU32BE(0x04); err != nil || u != 0x80 {
+		t.Errorf("ReadRegU32BE = 0x%08X, %v", u, err)
+	}
+	if s, err := v.ReadRegS32LE(0x04); err != nil || s != -0x80000000 {
+		t.Errorf("ReadRegS32LE = %d, %v", s, err)
+	}
+	if u, err := v.ReadRegU32LE(0x04); err != nil || u != 0x80000000 {
+		t.Errorf("ReadRegU32LE = 0x%08X, %v", u, err)
+	}
+
+	a.reset()
+	writes := []func() error{
+		func() error { return v.WriteRegU32BE(0x10, 0x01020304) },
+		func() error { return v.WriteRegU32LE(0x10, 0x01020304) },
+		func() error { return v.WriteRegS32BE(0x10, -2) },
+		func() error { return v.WriteRegS32LE(0x10, -2) },
+	}
+	want := [][]byte{
+		{0x10, 0x01, 0x02, 0x03, 0x04},
+		{0x10, 0x04, 0x03, 0x02, 0x01},
+		{0x10, 0xFF, 0xFF, 0xFF, 0xFE},
+		{0x10, 0xFE, 0xFF, 0xFF, 0xFF},
+	}
+	for _, write := range writes {
+		if err := write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := a.writes()
+	for i := range want {
+		if i >= len(w) || !bytes.Equal(w[i], want[i]) {
+			t.Fatalf("writes %X, want %X", w, want)
+		}
+	}
+}
+
+func TestNewForce(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x3C).regs[0x00] = 0x77
+	a.chip(0x3D)
+	a.busy[0x3C] = true
+	a.busy[0x3D] = true
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	quietLog(t)
+
+	if _, err := NewI2C(0x3C, 1); !errors.Is(err, syscall.EBUSY) {
+		t.Fatalf("NewI2C on claimed address: %v, want EBUSY", err)
+	}
+	a.reset()
+	v, err := NewForce(0x3C, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if b, err := v.ReadRegU8(0x00); err != nil || b != 0x77 {
+		t.Errorf("ReadRegU8 = 0x%02X, %v", b, err)
+	}
+	// Address switch stays forced.
+	if err := v.SetAddr(0x3D); err != nil {
+		t.Fatal(err)
+	}
+	var slaves []fakeOp
+	for _, op := range a.operations("ioctl") {
+		if op.cmd == I2C_SLAVE || op.cmd == I2C_SLAVE_FORCE {
+			slaves = append(slaves, op)
+		}
+	}
+	if len(slaves) != 2 {
+		t.Fatalf("address ioctls %+v, want 2", slaves)
+	}
+	for i, addr := range []uintptr{0x3C, 0x3D} {
+		if slaves[i].cmd != 0x0706 || slaves[i].arg != addr {
+			t.Errorf("ioctl 0x%04X(0x%X), want I2C_SLAVE_FORCE (0x0706) with 0x%X",
+				slaves[i].cmd, slaves[i].arg, addr)
+		}
+	}
+}
+
+func TestRegS8(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	tests := []struct {
+		raw   byte
+		value int8
+	}{
+		{0x00, 0},
+		{0x7F, 127},
+		{0x80, -128},
+		{0xFF, -1},
+	}
+	for _, test := range tests {
+		c.regs[0x07] = test.raw
+		if b, err := v.ReadRegS8(0x07); err != nil || b != test.value {
+			t.Errorf("0x%02X read as %d, %v, want %d", test.raw, b, err, test.value)
+		}
+		a.reset()
+		if err := v.WriteRegS8(0x08, test.value); err != nil {
+			t.Fatal(err)
+		}
+		if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x08, test.raw}) {
+			t.Errorf("%d written as %X, want [08 %02X]", test.value, w, test.raw)
+		}
+	}
+}
+
+func TestWriteRegBytes(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	data := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+	if err := v.WriteRegBytes(0x30, data); err != nil {
+		t.Fatal(err)
+	}
+	// Data slice of caller stays untouched.
+	if !bytes.Equal(data, []byte{0xDE, 0xAD, 0xBE, 0xEF}) {
+		t.Errorf("data modified to [% X]", data)
+	}
+	ops := a.operations()
+	if len(ops) != 1 || ops[0].kind != "write" ||
+		!bytes.Equal(ops[0].data, []byte{0x30, 0xDE, 0xAD, 0xBE, 0xEF}) {
+		t.Errorf("operations %+v, want one write of [30 DE AD BE EF]", ops)
+	}
+	if c := a.chip(0x40); !bytes.Equal(c.regs[0x30:0x34], data) {
+		t.Errorf("registers [% X]", c.regs[0x30:0x34])
+	}
+}
+
+// BenchmarkReadRegInto shows, that register read into reused buffer
+// doesn't allocate, while debug output is off.
+func BenchmarkReadRegInto(b *testing.B) {
+	v, a, _ := newFake(b, 0x40)
+	quietLog(b)
+	a.norecord = true
+	buf := make([]byte, 6)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := v.ReadRegInto(0x10, buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestNewAtPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "i2c-9")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Regular file opens, but rejects slave address ioctl.
+	if _, err := NewAtPath(path, 0x40); !errors.Is(err, syscall.ENOTTY) {
+		t.Errorf("NewAtPath on regular file: %v, want ENOTTY", err)
+	}
+
+	a := newFakeAdapter()
+	a.chip(0x40).regs[0x00] = 0x42
+	quietLog(t)
+	open := openDevice
+	t.Cleanup(func() { openDevice = open })
+	var opened []string
+	openDevice = func(p string) (device, error) {
+		opened = append(opened, p)
+		return a.open(), nil
+	}
+	v, err := NewAtPath(path, 0x40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if len(opened) != 1 || opened[0] != path {
+		t.Errorf("opened %q, want %q", opened, path)
+	}
+	if ops := a.operations("ioctl"); len(ops) != 1 || ops[0].cmd != I2C_SLAVE || ops[0].arg != 0x40 {
+		t.Errorf("ioctls %+v, want I2C_SLAVE 0x40", ops)
+	}
+	if v.GetBus() != 9 || v.GetPath() != path {
+		t.Errorf("bus %d, path %q", v.GetBus(), v.GetPath())
+	}
+	if b, err := v.ReadRegU8(0x00); err != nil || b != 0x42 {
+		t.Errorf("ReadRegU8 = 0x%02X, %v", b, err)
+	}
+	// Reopen uses the same path.
+	if err := v.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 2 || opened[1] != path {
+		t.Errorf("reopened %q, want %q", opened, path)
+	}
+
+	v, err = NewAtPath(filepath.Join(t.TempDir(), "i2c-bridge"), 0x40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if v.GetBus() != -1 {
+		t.Errorf("bus %d for path without number, want -1", v.GetBus())
+	}
+}
+
+func TestSetAddr(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x41).regs[0x00] = 0x41
+	if err := v.SetAddr(0x41); err != nil {
+		t.Fatal(err)
+	}
+	if ops := a.operations(); len(ops) != 1 || ops[0].kind != "ioctl" ||
+		ops[0].cmd != I2C_SLAVE || ops[0].arg != 0x41 {
+		t.Errorf("operations %+v, want I2C_SLAVE 0x41", ops)
+	}
+	if v.GetAddr() != 0x41 || v.GetAddr16() != 0x41 {
+		t.Errorf("GetAddr = 0x%X, GetAddr16 = 0x%X, want 0x41", v.GetAddr(), v.GetAddr16())
+	}
+	// The same descriptor is used.
+	if a.opens != 1 {
+		t.Errorf("device opened %d times", a.opens)
+	}
+	if b, err := v.ReadRegU8(0x00); err != nil || b != 0x41 {
+		t.Errorf("ReadRegU8 = 0x%02X, %v", b, err)
+	}
+	a.busy[0x42] = true
+	if err := v.SetAddr(0x42); !errors.Is(err, syscall.EBUSY) {
+		t.Errorf("SetAddr error %v, want EBUSY", err)
+	}
+	if v.GetAddr() != 0x41 {
+		t.Errorf("address changed to 0x%X by failed switch", v.GetAddr())
+	}
+}
+
+func TestWithAddr(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x70)
+	buf := make([]byte, 1)
+	err := v.WithAddr(0x70, func() error {
+		if v.GetAddr() != 0x70 {
+			t.Errorf("address 0x%X inside fn, want 0x70", v.GetAddr())
+		}
+		// Select mux channel 2 and read it back.
+		if _, err := v.WriteBytes([]byte{0x04}); err != nil {
+			return err
+		}
+		_, err := v.ReadBytes(buf)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf[0] != 0x00 || v.GetAddr() != 0x40 {
+		t.Errorf("read 0x%02X, address after 0x%X, want 0x40", buf[0], v.GetAddr())
+	}
+	var trace []string
+	for _, op := range a.operations() {
+		trace = append(trace, fmt.Sprintf("%s:%X", op.kind, op.addr))
+		if op.kind == "ioctl" {
+			trace[len(trace)-1] = fmt.Sprintf("ioctl:%X", op.arg)
+		}
+	}
+	want := []string{"ioctl:70", "write:70", "read:70", "ioctl:40"}
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("operations %v, want %v", trace, want)
+	}
+
+	// Previous address is restored after fn failure too.
+	fail := errors.New("fail")
+	if err := v.WithAddr(0x70, func() error { return fail }); err != fail {
+		t.Errorf("WithAddr error %v, want fn error", err)
+	}
+	if v.GetAddr() != 0x40 {
+		t.Errorf("address 0x%X after failed fn, want 0x40", v.GetAddr())
+	}
+}
+
+func TestSetAddrTenBit(t *testing.T) {
+	v, a := newTenBitFake(t, 0x3A5)
+	if err := v.SetAddr(0x40); err == nil {
+		t.Error("10-bit connection switched to 7-bit address")
+	}
+	called := false
+	if err := v.WithAddr(0x40, func() error { called = true; return nil }); err == nil || called {
+		t.Errorf("WithAddr on 10-bit connection: %v, fn called %v", err, called)
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("operations %+v, want none", ops)
+	}
+	if v.GetAddr16() != 0x3A5 || !v.tenBit {
+		t.Errorf("connection moved to address 0x%X", v.GetAddr16())
+	}
+}
+
+func TestReadRegU16LE(t *testing.T) {
+	v, a, _ := newFake(t, 0x40, WithCombinedReads())
+	copy(a.chip(0x40).regs[0x10:], []byte{0xFE, 0xFF})
+	l := captureLog(t)
+	if w, err := v.ReadRegU16LE(0x10); err != nil || w != 0xFFFE {
+		t.Fatalf("ReadRegU16LE = 0x%04X, %v", w, err)
+	}
+	if w, err := v.ReadRegS16LE(0x10); err != nil || w != -2 {
+		t.Fatalf("ReadRegS16LE = %d, %v", w, err)
+	}
+	// Each read is one combined transaction: address write
+	// and data read messages of single I2C_RDWR call.
+	ops := a.operations()
+	if len(ops) != 4 {
+		t.Fatalf("operations %+v, want 2 combined transactions", ops)
+	}
+	for i, op := range ops {
+		kind := []string{"write", "read"}[i%2]
+		if !op.rdwr || op.kind != kind {
+			t.Errorf("operation %d: %+v, want combined %s", i, op, kind)
+		}
+	}
+	// Logged value is decoded in little endian order.
+	var logged []string
+	for _, line := range l.output() {
+		if strings.Contains(line, "Read U16") {
+			logged = append(logged, line)
+		}
+	}
+	want := "DEBUG Read U16 65534 (LittleEndian) from reg 0x10"
+	if len(logged) != 2 || logged[0] != want || logged[1] != want {
+		t.Errorf("logged %q, want %q twice", logged, want)
+	}
+}
+
+func TestWriteRegBytesWait(t *testing.T) {
+	v, a, clock := newFake(t, 0x50)
+	if err := v.WriteRegBytesWait(0x00, []byte{0x01}, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 5*time.Millisecond {
+		t.Errorf("delays %v, want [5ms]", slept)
+	}
+
+	// With real time, read issued meanwhile waits for the delay.
+	const after = 30 * time.Millisecond
+	v.SetClock(nil)
+	c := a.chip(0x50)
+	var wrote, read time.Time
+	started := make(chan struct{})
+	c.onWrite = func(c *fakeChip) {
+		if wrote.IsZero() {
+			wrote = time.Now()
+			close(started)
+		}
+	}
+	c.onRead = func(c *fakeChip) {
+		read = time.Now()
+	}
+	done := make(chan error)
+	go func() {
+		<-started
+		_, err := v.ReadRegU8(0x00)
+		done <- err
+	}()
+	start := time.Now()
+	if err := v.WriteRegBytesWait(0x00, []byte{0x02, 0x03}, after); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < after {
+		t.Errorf("WriteRegBytesWait returned after %v, want at least %v", elapsed, after)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if d := read.Sub(wrote); d < after {
+		t.Errorf("read %v after write, want at least %v", d, after)
+	}
+}
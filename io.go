@@ -0,0 +1,20 @@
+package i2c
+
+import "io"
+
+// I2C-connection can be used with standard io plumbing
+// (io.Copy, bufio.Reader and so on).
+var _ io.ReadWriteCloser = (*I2C)(nil)
+
+// Read implements io.Reader: reads len(p) bytes from I2C-device,
+// starting from current device pointer (register address set
+// by previous write, or device-specific position), like ReadBytes.
+func (v *I2C) Read(p []byte) (int, error) {
+	return v.ReadBytes(p)
+}
+
+// Write implements io.Writer: sends p to I2C-device
+// in single transfer, like WriteBytes.
+func (v *I2C) Write(p []byte) (int, error) {
+	return v.WriteBytes(p)
+}
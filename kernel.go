@@ -0,0 +1,71 @@
+package i2c
+
+import "unsafe"
+
+// Go mirrors of Linux kernel structures passed by pointer
+// to I2C ioctls (see <linux/i2c.h> and <linux/i2c-dev.h>).
+// Field types are chosen so that size and alignment match
+// the kernel layout on both 32-bit and 64-bit platforms:
+// pointers occupy a native word, so padding is inserted
+// by the compiler exactly where the C compiler puts it.
+
+// i2cSmbusBlockMax is the maximum SMBus block length
+// as specified in SMBus standard.
+const i2cSmbusBlockMax = 32
+
+// i2cMsg corresponds to struct i2c_msg.
+type i2cMsg struct {
+	addr  uint16
+	flags uint16
+	len   uint16
+	buf   *byte
+}
+
+// i2cRdwrIoctlData corresponds to struct i2c_rdwr_ioctl_data.
+type i2cRdwrIoctlData struct {
+	msgs  *i2cMsg
+	nmsgs uint32
+}
+
+// i2cSmbusData corresponds to union i2c_smbus_data:
+// byte, word and block[] share the same storage,
+// block[0] is used for length.
+type i2cSmbusData [i2cSmbusBlockMax + 2]byte
+
+// i2cSmbusIoctlData corresponds to struct i2c_smbus_ioctl_data.
+type i2cSmbusIoctlData struct {
+	readWrite uint8
+	command   uint8
+	size      uint32
+	data      *i2cSmbusData
+}
+
+// ptrSize is a size of native pointer: 4 bytes on 32-bit
+// platforms (386, arm), 8 bytes on 64-bit (amd64, arm64).
+const ptrSize = unsafe.Sizeof(uintptr(0))
+
+// Expected kernel structure sizes for current architecture.
+const (
+	sizeofI2cMsg            = 8 + ptrSize
+	sizeofI2cRdwrIoctlData  = 2 * ptrSize
+	sizeofI2cSmbusData      = i2cSmbusBlockMax + 2
+	sizeofI2cSmbusIoctlData = 8 + ptrSize
+)
+
+// Compile-time size assertions: passing structure of wrong size
+// to the kernel corrupts memory, so break the build instead.
+// Each pair of array lengths turns into a negative constant
+// (which can't be represented as uintptr) when sizes differ.
+var (
+	_ [unsafe.Sizeof(i2cMsg{}) - sizeofI2cMsg]struct{}
+	_ [sizeofI2cMsg - unsafe.Sizeof(i2cMsg{})]struct{}
+
+	_ [unsafe.Sizeof(i2cRdwrIoctlData{}) - sizeofI2cRdwrIoctlData]struct{}
+	_ [sizeofI2cRdwrIoctlData - unsafe.Sizeof(i2cRdwrIoctlData{})]struct{}
+
+	_ [unsafe.Sizeof(i2cSmbusData{}) - sizeofI2cSmbusData]struct{}
+	_ [sizeofI2cSmbusData - unsafe.Sizeof(i2cSmbusData{})]struct{}
+
+	_ [unsafe.Sizeof(i2cSmbusIoctlData{}) - sizeofI2cSmbusIoctlData]struct{}
+	_ [sizeofI2cSmbusIoctlData - unsafe.Sizeof(i2cSmbusIoctlData{})]struct{}
+)
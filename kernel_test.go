@@ -0,0 +1,36 @@
+package i2c
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+func TestKernelStructLayout(t *testing.T) {
+	type layout struct {
+		msg, msgBuf, rdwr, smbusData, smbus, smbusPtr uintptr
+	}
+	// Sizes and offsets of pointer fields, as C compiler lays out
+	// structures from <linux/i2c.h> and <linux/i2c-dev.h>.
+	expected := map[string]layout{
+		"386":   {msg: 12, msgBuf: 8, rdwr: 8, smbusData: 34, smbus: 12, smbusPtr: 8},
+		"arm":   {msg: 12, msgBuf: 8, rdwr: 8, smbusData: 34, smbus: 12, smbusPtr: 8},
+		"amd64": {msg: 16, msgBuf: 8, rdwr: 16, smbusData: 34, smbus: 16, smbusPtr: 8},
+		"arm64": {msg: 16, msgBuf: 8, rdwr: 16, smbusData: 34, smbus: 16, smbusPtr: 8},
+	}
+	want, ok := expected[runtime.GOARCH]
+	if !ok {
+		t.Skipf("no reference layout for GOARCH=%s", runtime.GOARCH)
+	}
+	got := layout{
+		msg:       unsafe.Sizeof(i2cMsg{}),
+		msgBuf:    unsafe.Offsetof(i2cMsg{}.buf),
+		rdwr:      unsafe.Sizeof(i2cRdwrIoctlData{}),
+		smbusData: unsafe.Sizeof(i2cSmbusData{}),
+		smbus:     unsafe.Sizeof(i2cSmbusIoctlData{}),
+		smbusPtr:  unsafe.Offsetof(i2cSmbusIoctlData{}.data),
+	}
+	if got != want {
+		t.Errorf("GOARCH=%s: layout %+v, want %+v", runtime.GOARCH, got, want)
+	}
+}
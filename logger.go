@@ -1,11 +1,142 @@
 package i2c
 
-import logger "github.com/d2r2/go-logger"
-
-// You can manage verbosity of log output
-// in the package by changing last parameter value
-// (comment/uncomment corresponding lines).
-var lg = logger.NewPackageLogger("i2c",
-	logger.DebugLevel,
-	// logger.InfoLevel,
+import "sync"
+
+// Logger is a minimal logging interface used by the package,
+// so output can be routed to application logging library
+// (slog, zap, logrus and so on) with SetLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// LogLevel is verbosity of package log output.
+type LogLevel int
+
+// Log levels, from the least verbose one.
+const (
+	// QuietLevel suppress all messages.
+	QuietLevel LogLevel = iota
+	ErrorLevel
+	InfoLevel
+	DebugLevel
 )
+
+// Package logs nothing, until logger is installed with SetLogger
+// (see subpackage gologger to route output to go-logger).
+var lg = &levelLogger{out: nopLogger{}, level: DebugLevel}
+
+// levelLogger pass to out only messages,
+// which are not more verbose than level.
+type levelLogger struct {
+	mu    sync.RWMutex
+	out   Logger
+	level LogLevel
+}
+
+// get return current logger and level.
+func (l *levelLogger) get() (Logger, LogLevel) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.out, l.level
+}
+
+// enabled return true, if messages of level pass to output. Use it to
+// skip formatting of costly arguments in hot paths.
+func (l *levelLogger) enabled(level LogLevel) bool {
+	out, current := l.get()
+	if _, ok := out.(nopLogger); ok {
+		return false
+	}
+	return current >= level
+}
+
+func (l *levelLogger) Debugf(format string, args ...interface{}) {
+	if out, level := l.get(); level >= DebugLevel {
+		out.Debugf(format, args...)
+	}
+}
+
+func (l *levelLogger) Infof(format string, args ...interface{}) {
+	if out, level := l.get(); level >= InfoLevel {
+		out.Infof(format, args...)
+	}
+}
+
+func (l *levelLogger) Errorf(format string, args ...interface{}) {
+	if out, level := l.get(); level >= ErrorLevel {
+		out.Errorf(format, args...)
+	}
+}
+
+// connLogger pass log messages of connection to package logger,
+// suppressing those more verbose than connection level, if it has
+// been set with WithLogLevel.
+type connLogger struct {
+	set   bool
+	level LogLevel
+}
+
+// allow return true, if connection level pass messages of level.
+func (c connLogger) allow(level LogLevel) bool {
+	return !c.set || c.level >= level
+}
+
+func (c connLogger) enabled(level LogLevel) bool {
+	return c.allow(level) && lg.enabled(level)
+}
+
+func (c connLogger) Debugf(format string, args ...interface{}) {
+	if c.allow(DebugLevel) {
+		lg.Debugf(format, args...)
+	}
+}
+
+func (c connLogger) Infof(format string, args ...interface{}) {
+	if c.allow(InfoLevel) {
+		lg.Infof(format, args...)
+	}
+}
+
+func (c connLogger) Errorf(format string, args ...interface{}) {
+	if c.allow(ErrorLevel) {
+		lg.Errorf(format, args...)
+	}
+}
+
+// nopLogger discard all output.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+// SetLogger replace logger used by the package (no-op one, discarding
+// all output, by default). Nil value disable logging at all.
+// Logger is shared by all connections and may be replaced
+// at any time, even while connections are in use.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.out = l
+}
+
+// SetLogLevel set verbosity of package log output: messages more
+// verbose than level (DebugLevel, InfoLevel, ErrorLevel) are
+// suppressed, whichever logger is used. QuietLevel make package quiet.
+// Default is DebugLevel. Like logger, level is shared by all
+// connections and may be changed at any time.
+func SetLogLevel(level LogLevel) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.level = level
+}
+
+// SetQuiet disable all package log output, see SetLogLevel.
+func SetQuiet() {
+	SetLogLevel(QuietLevel)
+}
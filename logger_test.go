@@ -0,0 +1,125 @@
+package i2c
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	out, level := lg.get()
+	if _, ok := out.(nopLogger); !ok {
+		t.Errorf("default logger %T, want no-op one", out)
+	}
+	if level != DebugLevel {
+		t.Errorf("default level %v, want DebugLevel", level)
+	}
+	// Arguments of costly messages aren't formatted for no-op logger.
+	if lg.enabled(DebugLevel) {
+		t.Error("debug output enabled for no-op logger")
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	l := captureLog(t)
+	SetLogLevel(InfoLevel)
+	v, err := NewI2C(0x40, 1, WithDryRun(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	out := l.output()
+	if len(out) == 0 || !strings.HasPrefix(out[0], "INFO Dry-run mode active") {
+		t.Errorf("info message missing: %q", out)
+	}
+	for _, line := range out {
+		if strings.HasPrefix(line, "DEBUG") {
+			t.Errorf("debug message at info level: %q", line)
+		}
+	}
+
+	SetLogLevel(DebugLevel)
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(l.output()); n == len(out) {
+		t.Error("no debug messages at debug level")
+	}
+
+	SetQuiet()
+	n := len(l.output())
+	if _, err := NewI2C(0x41, 1, WithDryRun(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if out := l.output(); len(out) != n {
+		t.Errorf("quiet mode logged %q", out[n:])
+	}
+}
+
+func TestWithLogLevel(t *testing.T) {
+	l := captureLog(t)
+	quiet, err := NewI2C(0x40, 1, WithDryRun(nil), WithLogLevel(QuietLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer quiet.Close()
+	info, err := NewI2C(0x41, 1, WithDryRun(nil), WithLogLevel(InfoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer info.Close()
+	out := l.output()
+	if len(out) != 1 || !strings.Contains(out[0], "address 0x41") {
+		t.Errorf("construction logged %q, want info line of 0x41 only", out)
+	}
+	if _, err := quiet.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := info.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if out := l.output(); len(out) != 1 {
+		t.Errorf("debug messages logged by connections above debug level: %q", out[1:])
+	}
+	// Other connections keep package level.
+	v, err := NewI2C(0x42, 1, WithDryRun(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	n := len(l.output())
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.output()) == n {
+		t.Error("no debug messages from connection at package level")
+	}
+}
+
+func TestLogLevelConcurrent(t *testing.T) {
+	v, _, _ := newFake(t, 0x40)
+	captureLog(t)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := v.ReadRegU8(0x10); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			SetLogLevel(InfoLevel)
+			SetLogger(&captureLogger{})
+			SetLogLevel(DebugLevel)
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,37 @@
+package i2c
+
+import "fmt"
+
+// ReadRegMapped reads byte from I2C-device register specified in reg
+// and returns its label from table, which maps enumerated register
+// states to meaningful names. Returns ErrUnknownValue,
+// if value is missing in table.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegMapped(reg byte, table map[byte]string) (string, error) {
+	b, err := v.ReadRegU8(reg)
+	if err != nil {
+		return "", err
+	}
+	label, ok := table[b]
+	if !ok {
+		return "", fmt.Errorf("%w: 0x%0X read from reg 0x%0X", ErrUnknownValue, b, reg)
+	}
+	return label, nil
+}
+
+// ReadRegMappedFloat reads byte from I2C-device register specified
+// in reg and returns numeric value from table, which maps register
+// codes to factors (gain code to gain factor, for instance).
+// Returns ErrUnknownValue, if value is missing in table.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegMappedFloat(reg byte, table map[byte]float64) (float64, error) {
+	b, err := v.ReadRegU8(reg)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := table[b]
+	if !ok {
+		return 0, fmt.Errorf("%w: 0x%0X read from reg 0x%0X", ErrUnknownValue, b, reg)
+	}
+	return f, nil
+}
@@ -0,0 +1,29 @@
+package i2c
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadRegMapped(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	labels := map[byte]string{0x00: "1x", 0x01: "2x"}
+	factors := map[byte]float64{0x00: 1, 0x01: 2.5}
+
+	c.regs[0x0C] = 0x01
+	if label, err := v.ReadRegMapped(0x0C, labels); err != nil || label != "2x" {
+		t.Errorf("ReadRegMapped = %q, %v", label, err)
+	}
+	if f, err := v.ReadRegMappedFloat(0x0C, factors); err != nil || f != 2.5 {
+		t.Errorf("ReadRegMappedFloat = %v, %v", f, err)
+	}
+
+	c.regs[0x0C] = 0x07
+	if _, err := v.ReadRegMapped(0x0C, labels); !errors.Is(err, ErrUnknownValue) {
+		t.Errorf("expected ErrUnknownValue, got %v", err)
+	}
+	if _, err := v.ReadRegMappedFloat(0x0C, factors); !errors.Is(err, ErrUnknownValue) {
+		t.Errorf("expected ErrUnknownValue, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+// Package mock provides in-memory I2C-device, implementing i2c.Conn,
+// to unit test device drivers without hardware.
+//
+// Device emulates common register map model: the first byte of every
+// write sets register pointer, following bytes are stored starting
+// from it, while reads return bytes starting from register pointer.
+// Pointer auto-increments after each byte (wrapping at 0xFF).
+package mock
+
+import (
+	"errors"
+	"sync"
+
+	i2c "github.com/d2r2/go-i2c"
+)
+
+// ErrClosed returned on access to closed Device.
+var ErrClosed = errors.New("mock: device closed")
+
+// Device is in-memory I2C-device with 256 byte registers.
+// Device is safe for concurrent use.
+type Device struct {
+	mu     sync.Mutex
+	regs   [256]byte
+	ptr    byte
+	closed bool
+}
+
+var _ i2c.Conn = (*Device)(nil)
+
+// NewDevice create Device with registers preset from regs.
+func NewDevice(regs map[byte]byte) *Device {
+	d := &Device{}
+	for reg, b := range regs {
+		d.regs[reg] = b
+	}
+	return d
+}
+
+// Reg return current value of register reg.
+func (d *Device) Reg(reg byte) byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.regs[reg]
+}
+
+// SetReg change value of register reg, as device itself
+// would do (new measurement, for instance).
+func (d *Device) SetReg(reg byte, value byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.regs[reg] = value
+}
+
+// WriteBytes set register pointer to buf[0]
+// and store the rest of buf starting from it.
+func (d *Device) WriteBytes(buf []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeBytes(buf)
+}
+
+// writeBytes is WriteBytes, which doesn't acquire device lock.
+func (d *Device) writeBytes(buf []byte) (int, error) {
+	if d.closed {
+		return 0, ErrClosed
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	d.ptr = buf[0]
+	for _, b := range buf[1:] {
+		d.regs[d.ptr] = b
+		d.ptr++
+	}
+	return len(buf), nil
+}
+
+// ReadBytes read len(buf) bytes starting from register pointer.
+func (d *Device) ReadBytes(buf []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readBytes(buf)
+}
+
+// readBytes is ReadBytes, which doesn't acquire device lock.
+func (d *Device) readBytes(buf []byte) (int, error) {
+	if d.closed {
+		return 0, ErrClosed
+	}
+	for i := range buf {
+		buf[i] = d.regs[d.ptr]
+		d.ptr++
+	}
+	return len(buf), nil
+}
+
+// ReadRegBytes read n bytes starting from register reg. Pointer
+// write and read are done under one device lock, so concurrent
+// access can't move pointer in between.
+func (d *Device) ReadRegBytes(reg byte, n int) ([]byte, int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.writeBytes([]byte{reg}); err != nil {
+		return nil, 0, err
+	}
+	buf := make([]byte, n)
+	c, err := d.readBytes(buf)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, c, nil
+}
+
+// ReadRegU8 read byte from register reg.
+func (d *Device) ReadRegU8(reg byte) (byte, error) {
+	buf, _, err := d.ReadRegBytes(reg, 1)
+	if err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
+
+// WriteRegU8 write byte to register reg.
+func (d *Device) WriteRegU8(reg byte, value byte) error {
+	_, err := d.WriteBytes([]byte{reg, value})
+	return err
+}
+
+// ReadRegU16BE read big endian word starting from register reg.
+func (d *Device) ReadRegU16BE(reg byte) (uint16, error) {
+	buf, _, err := d.ReadRegBytes(reg, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
+
+// ReadRegU16LE read little endian word starting from register reg.
+func (d *Device) ReadRegU16LE(reg byte) (uint16, error) {
+	buf, _, err := d.ReadRegBytes(reg, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(buf[1])<<8 | uint16(buf[0]), nil
+}
+
+// ReadRegS16BE read signed big endian word starting from register reg.
+func (d *Device) ReadRegS16BE(reg byte) (int16, error) {
+	w, err := d.ReadRegU16BE(reg)
+	return int16(w), err
+}
+
+// ReadRegS16LE read signed little endian word starting from register reg.
+func (d *Device) ReadRegS16LE(reg byte) (int16, error) {
+	w, err := d.ReadRegU16LE(reg)
+	return int16(w), err
+}
+
+// WriteRegU16BE write big endian word starting from register reg.
+func (d *Device) WriteRegU16BE(reg byte, value uint16) error {
+	_, err := d.WriteBytes([]byte{reg, byte(value >> 8), byte(value)})
+	return err
+}
+
+// WriteRegU16LE write little endian word starting from register reg.
+func (d *Device) WriteRegU16LE(reg byte, value uint16) error {
+	_, err := d.WriteBytes([]byte{reg, byte(value), byte(value >> 8)})
+	return err
+}
+
+// WriteRegS16BE write signed big endian word starting from register reg.
+func (d *Device) WriteRegS16BE(reg byte, value int16) error {
+	return d.WriteRegU16BE(reg, uint16(value))
+}
+
+// WriteRegS16LE write signed little endian word starting from register reg.
+func (d *Device) WriteRegS16LE(reg byte, value int16) error {
+	return d.WriteRegU16LE(reg, uint16(value))
+}
+
+// Close mark device closed: further access fails with ErrClosed.
+func (d *Device) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
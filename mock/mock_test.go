@@ -0,0 +1,134 @@
+package mock_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	i2c "github.com/d2r2/go-i2c"
+	"github.com/d2r2/go-i2c/mock"
+)
+
+// thermometer is sample driver of LM75-like sensor, which accepts
+// i2c.Conn, so it works with both real connection and mock device.
+type thermometer struct {
+	conn i2c.Conn
+}
+
+// Temperature return temperature in degrees Celsius from register 0,
+// kept as signed big endian word in 1/256 degree units.
+func (t *thermometer) Temperature() (float64, error) {
+	w, err := t.conn.ReadRegS16BE(0x00)
+	if err != nil {
+		return 0, err
+	}
+	return float64(w) / 256, nil
+}
+
+// Shutdown set shutdown bit in configuration register 0x10.
+func (t *thermometer) Shutdown() error {
+	b, err := t.conn.ReadRegU8(0x10)
+	if err != nil {
+		return err
+	}
+	return t.conn.WriteRegU8(0x10, b|0x01)
+}
+
+func ExampleDevice() {
+	dev := mock.NewDevice(map[byte]byte{0x00: 0x19, 0x10: 0x80})
+	sensor := &thermometer{conn: dev}
+
+	t, _ := sensor.Temperature()
+	fmt.Printf("%.1f C\n", t)
+	// Device changes register, as new measurement would do.
+	dev.SetReg(0x00, 0xE7)
+	t, _ = sensor.Temperature()
+	fmt.Printf("%.1f C\n", t)
+
+	_ = sensor.Shutdown()
+	fmt.Printf("config 0x%02X\n", dev.Reg(0x10))
+	// Output:
+	// 25.0 C
+	// -25.0 C
+	// config 0x81
+}
+
+func TestDeviceRegisters(t *testing.T) {
+	dev := mock.NewDevice(nil)
+	if err := dev.WriteRegU16BE(0x10, 0x1234); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WriteRegU16LE(0x20, 0x1234); err != nil {
+		t.Fatal(err)
+	}
+	if dev.Reg(0x10) != 0x12 || dev.Reg(0x11) != 0x34 ||
+		dev.Reg(0x20) != 0x34 || dev.Reg(0x21) != 0x12 {
+		t.Errorf("registers stored in wrong order")
+	}
+	if w, err := dev.ReadRegU16LE(0x10); err != nil || w != 0x3412 {
+		t.Errorf("ReadRegU16LE = 0x%04X, %v", w, err)
+	}
+	if err := dev.WriteRegS16LE(0x30, -2); err != nil {
+		t.Fatal(err)
+	}
+	if w, err := dev.ReadRegS16LE(0x30); err != nil || w != -2 {
+		t.Errorf("ReadRegS16LE = %d, %v", w, err)
+	}
+	if w, err := dev.ReadRegS16BE(0x30); err != nil || w != -257 {
+		t.Errorf("ReadRegS16BE = %d, %v", w, err)
+	}
+
+	// Pointer auto-increments and wraps at 0xFF.
+	if _, err := dev.WriteBytes([]byte{0xFF, 0xAA, 0xBB}); err != nil {
+		t.Fatal(err)
+	}
+	if dev.Reg(0xFF) != 0xAA || dev.Reg(0x00) != 0xBB {
+		t.Errorf("write didn't wrap: 0x%02X 0x%02X", dev.Reg(0xFF), dev.Reg(0x00))
+	}
+	buf := make([]byte, 2)
+	if _, err := dev.WriteBytes([]byte{0xFF}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.ReadBytes(buf); err != nil || buf[0] != 0xAA || buf[1] != 0xBB {
+		t.Errorf("ReadBytes = [% X], %v", buf, err)
+	}
+
+	if err := dev.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.ReadRegU8(0x00); err != mock.ErrClosed {
+		t.Errorf("read from closed device: %v", err)
+	}
+	if err := dev.WriteRegU8(0x00, 0); err != mock.ErrClosed {
+		t.Errorf("write to closed device: %v", err)
+	}
+}
+
+func TestDeviceConcurrentReads(t *testing.T) {
+	regs := make(map[byte]byte)
+	for i := 0; i < 256; i++ {
+		regs[byte(i)] = byte(i)
+	}
+	dev := mock.NewDevice(regs)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(reg byte) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				// Another goroutine can't move pointer between
+				// register address write and read.
+				buf, _, err := dev.ReadRegBytes(reg, 2)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if buf[0] != reg || buf[1] != reg+1 {
+					t.Errorf("ReadRegBytes(0x%02X) = [% X]", reg, buf)
+					return
+				}
+			}
+		}(byte(g * 16))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,107 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Stats is a summary of register samples collected by Monitor
+// over its window.
+type Stats struct {
+	// Count of samples in window.
+	Count int
+	Min   int32
+	Max   int32
+	Mean  float64
+	// Last is the most recent sample.
+	Last int32
+	// Err is error of the most recent read,
+	// nil if it succeeded.
+	Err error
+}
+
+// Monitor periodically reads register of I2C-device in background
+// and maintains running statistics over the latest samples,
+// kept in RingReader. Monitor is safe for concurrent use.
+type Monitor struct {
+	ring     *RingReader
+	interval time.Duration
+
+	mu  sync.Mutex
+	err error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewMonitor create and start Monitor, which every interval reads
+// sample of width bytes (1..4) from register reg, decoding it with
+// byte order specified in order, as signed (sign-extended) or unsigned
+// value, and keeps statistics over the latest window samples.
+// Intervals are measured with I2C-connection clock (see SetClock).
+// Call Stop to terminate background goroutine.
+func (v *I2C) NewMonitor(reg byte, width int, order binary.ByteOrder, signed bool,
+	interval time.Duration, window int) (*Monitor, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("sampling interval %v must be positive", interval)
+	}
+	ring, err := v.NewRingReader(reg, width, order, signed, window)
+	if err != nil {
+		return nil, err
+	}
+	m := &Monitor{ring: ring, interval: interval,
+		stop: make(chan struct{}), done: make(chan struct{})}
+	go m.run()
+	return m, nil
+}
+
+func (m *Monitor) run() {
+	defer close(m.done)
+	for {
+		err := m.ring.Sample()
+		m.mu.Lock()
+		m.err = err
+		m.mu.Unlock()
+		select {
+		case <-m.stop:
+			return
+		case <-m.ring.i2c.clock.After(m.interval):
+		}
+	}
+}
+
+// Summary return statistics over samples collected in window.
+func (m *Monitor) Summary() Stats {
+	m.mu.Lock()
+	st := Stats{Err: m.err}
+	m.mu.Unlock()
+	samples := m.ring.Latest(len(m.ring.ring))
+	st.Count = len(samples)
+	if st.Count == 0 {
+		return st
+	}
+	var sum int64
+	for i, s := range samples {
+		if i == 0 || s < st.Min {
+			st.Min = s
+		}
+		if i == 0 || s > st.Max {
+			st.Max = s
+		}
+		sum += int64(s)
+	}
+	st.Last = samples[len(samples)-1]
+	st.Mean = float64(sum) / float64(st.Count)
+	return st
+}
+
+// Stop terminate monitoring and wait for background goroutine
+// to finish. Summary remains available after Stop.
+// Stop may be called several times.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+	<-m.done
+}
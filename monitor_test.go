@@ -0,0 +1,76 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// tickClock is a fakeClock, which After channels are handed over
+// to test through waits, so test fires them one by one.
+type tickClock struct {
+	*fakeClock
+	waits chan chan time.Time
+}
+
+func (c tickClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.waits <- ch
+	return ch
+}
+
+func TestMonitor(t *testing.T) {
+	v, a, fake := newFake(t, 0x40)
+	clock := tickClock{fake, make(chan chan time.Time)}
+	v.SetClock(clock)
+	samples := []int8{10, -5, 20, 7}
+	n := 0
+	a.chip(0x40).onRead = func(c *fakeChip) {
+		c.regs[0x30] = byte(samples[n%len(samples)])
+		n++
+	}
+	m, err := v.NewMonitor(0x30, 1, binary.BigEndian, true, time.Second, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait for sample taken, then let next one go.
+	tick := func() chan time.Time { return <-clock.waits }
+
+	wait := tick()
+	if st := m.Summary(); st.Count != 1 || st.Min != 10 || st.Max != 10 ||
+		st.Mean != 10 || st.Last != 10 {
+		t.Errorf("summary after one sample %+v", st)
+	}
+	for i := 0; i < 3; i++ {
+		wait <- fake.Now()
+		wait = tick()
+	}
+	// Window keeps the latest 3 samples: -5, 20, 7.
+	st := m.Summary()
+	if st.Count != 3 || st.Min != -5 || st.Max != 20 || st.Mean != 22.0/3 ||
+		st.Last != 7 || st.Err != nil {
+		t.Errorf("summary over window %+v", st)
+	}
+
+	a.chip(0x40).nak = 1
+	wait <- fake.Now()
+	wait = tick()
+	if st := m.Summary(); st.Err == nil || st.Count != 3 || st.Last != 7 {
+		t.Errorf("summary after read failure %+v", st)
+	}
+	// Successful read clears error.
+	wait <- fake.Now()
+	tick()
+	if st := m.Summary(); st.Err != nil || st.Last != 10 {
+		t.Errorf("summary after recovery %+v", st)
+	}
+	m.Stop()
+	m.Stop()
+
+	if _, err := v.NewMonitor(0x30, 5, binary.BigEndian, true, time.Second, 3); err == nil {
+		t.Error("sample width 5 accepted")
+	}
+	if _, err := v.NewMonitor(0x30, 1, binary.BigEndian, true, 0, 3); err == nil {
+		t.Error("zero interval accepted")
+	}
+}
@@ -1,11 +1,78 @@
+//go:build !cgo
 // +build !cgo
 
 package i2c
 
-// Use hard-coded value for system I2C_SLAVE
-// constant, if OS not Linux or CGO disabled.
+// Use hard-coded values for system I2C ioctl
+// constants, if OS not Linux or CGO disabled.
 // This is not a good approach, but
 // can be used as a last resort.
 const (
-	I2C_SLAVE = 0x0703
+	I2C_RETRIES     = 0x0701
+	I2C_SLAVE       = 0x0703
+	I2C_SLAVE_FORCE = 0x0706
+	I2C_TENBIT      = 0x0704
+	I2C_FUNCS       = 0x0705
+	I2C_RDWR        = 0x0707
+	I2C_TIMEOUT     = 0x0702
+	I2C_PEC         = 0x0708
+	I2C_SMBUS       = 0x0720
+)
+
+// Maximum number of messages in single I2C_RDWR call.
+const I2C_RDWR_IOCTL_MAX_MSGS = 42
+
+// I2C message flags, used in
+// I2C_RDWR combined transactions.
+const (
+	I2C_M_RD           = 0x0001
+	I2C_M_TEN          = 0x0010
+	I2C_M_RECV_LEN     = 0x0400
+	I2C_M_NO_RD_ACK    = 0x0800
+	I2C_M_IGNORE_NAK   = 0x1000
+	I2C_M_REV_DIR_ADDR = 0x2000
+	I2C_M_NOSTART      = 0x4000
+	I2C_M_STOP         = 0x8000
+)
+
+// I2C adapter functionality flags,
+// returned by I2C_FUNCS ioctl.
+const (
+	I2C_FUNC_I2C                    = 0x00000001
+	I2C_FUNC_10BIT_ADDR             = 0x00000002
+	I2C_FUNC_PROTOCOL_MANGLING      = 0x00000004
+	I2C_FUNC_SMBUS_PEC              = 0x00000008
+	I2C_FUNC_NOSTART                = 0x00000010
+	I2C_FUNC_SLAVE                  = 0x00000020
+	I2C_FUNC_SMBUS_BLOCK_PROC_CALL  = 0x00008000
+	I2C_FUNC_SMBUS_QUICK            = 0x00010000
+	I2C_FUNC_SMBUS_READ_BYTE        = 0x00020000
+	I2C_FUNC_SMBUS_WRITE_BYTE       = 0x00040000
+	I2C_FUNC_SMBUS_READ_BYTE_DATA   = 0x00080000
+	I2C_FUNC_SMBUS_WRITE_BYTE_DATA  = 0x00100000
+	I2C_FUNC_SMBUS_READ_WORD_DATA   = 0x00200000
+	I2C_FUNC_SMBUS_WRITE_WORD_DATA  = 0x00400000
+	I2C_FUNC_SMBUS_PROC_CALL        = 0x00800000
+	I2C_FUNC_SMBUS_READ_BLOCK_DATA  = 0x01000000
+	I2C_FUNC_SMBUS_WRITE_BLOCK_DATA = 0x02000000
+	I2C_FUNC_SMBUS_READ_I2C_BLOCK   = 0x04000000
+	I2C_FUNC_SMBUS_WRITE_I2C_BLOCK  = 0x08000000
+	I2C_FUNC_SMBUS_HOST_NOTIFY      = 0x10000000
+)
+
+// SMBus transfer directions and transaction
+// types, used in I2C_SMBUS ioctl.
+const (
+	I2C_SMBUS_READ  = 1
+	I2C_SMBUS_WRITE = 0
+
+	I2C_SMBUS_QUICK            = 0
+	I2C_SMBUS_BYTE             = 1
+	I2C_SMBUS_BYTE_DATA        = 2
+	I2C_SMBUS_WORD_DATA        = 3
+	I2C_SMBUS_PROC_CALL        = 4
+	I2C_SMBUS_BLOCK_DATA       = 5
+	I2C_SMBUS_I2C_BLOCK_BROKEN = 6
+	I2C_SMBUS_BLOCK_PROC_CALL  = 7
+	I2C_SMBUS_I2C_BLOCK_DATA   = 8
 )
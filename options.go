@@ -0,0 +1,112 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Option configure I2C-connection at construction time.
+// Options are applied in order, before device is opened.
+type Option func(v *I2C) error
+
+// WithRequiredFuncs make NewI2C fail fast with ErrUnsupportedFunc,
+// if I2C adapter doesn't provide all functionality specified in flags
+// (combination of I2C_FUNC_... constants). It prevents discovering
+// mid-operation that, say, block transfers aren't supported.
+func WithRequiredFuncs(flags uint64) Option {
+	return func(v *I2C) error {
+		v.requiredFuncs |= flags
+		return nil
+	}
+}
+
+// WithTurnaroundDelay insert delay d between write phase (register
+// address) and read phase of every register read, as required by some
+// bit-banged or optocoupled half-duplex bridges. Default is zero.
+func WithTurnaroundDelay(d time.Duration) Option {
+	return func(v *I2C) error {
+		v.turnaround = d
+		return nil
+	}
+}
+
+// WithDryRun switch connection to simulated mode, where I2C-device
+// isn't accessed at all: writes are logged and discarded (reporting
+// success), while reads return pattern repeated (zeros, if pattern
+// is empty). Lets application logic run on machine without I2C bus.
+// Operations based on ioctl calls (combined transactions, SMBus
+// commands, adapter settings) aren't simulated and fail with ErrDryRun.
+func WithDryRun(pattern []byte) Option {
+	return func(v *I2C) error {
+		v.dryRun = true
+		v.dryPattern = append([]byte(nil), pattern...)
+		return nil
+	}
+}
+
+// WithMinReadInterval guarantee, that at least interval d elapse
+// between consecutive reads from I2C-device: read is delayed
+// just enough, if it comes too early. Enforces minimum conversion
+// period of rate-limited devices transparently. Default is zero.
+func WithMinReadInterval(d time.Duration) Option {
+	return func(v *I2C) error {
+		v.readPace.interval = d
+		return nil
+	}
+}
+
+// WithClock replace clock used by connection for delays
+// and timeouts, see SetClock.
+func WithClock(clock Clock) Option {
+	return func(v *I2C) error {
+		v.SetClock(clock)
+		return nil
+	}
+}
+
+// WithCombinedReads route all register reads (ReadRegBytes, ReadRegU8,
+// ReadRegU16BE and others) through combined I2C_RDWR transactions,
+// see ReadRegBytesRDWR. Adapter must support I2C_FUNC_I2C functionality.
+// Turnaround delay (see WithTurnaroundDelay) can't be inserted between
+// phases of combined transaction, so reads stay separate, if it is set.
+func WithCombinedReads() Option {
+	return func(v *I2C) error {
+		v.combined = true
+		return nil
+	}
+}
+
+// WithRegAddrOrder set byte order of 16-bit register (memory) addresses
+// used by ReadRegBytes16 and WriteRegBytes16. Most devices, including
+// 24xx EEPROMs, expect high byte first (binary.BigEndian), which
+// is default, but some vendors send low byte first.
+func WithRegAddrOrder(order binary.ByteOrder) Option {
+	return func(v *I2C) error {
+		v.regAddrOrder = order
+		return nil
+	}
+}
+
+// WithPEC enable SMBus packet error checking in kernel with I2C_PEC
+// ioctl: PEC byte is appended and verified by kernel for SMBus
+// commands. Plain read/write transfers aren't affected, use
+// ReadRegU8PEC and WriteRegU8PEC for them. Adapter must support
+// I2C_FUNC_SMBUS_PEC functionality.
+func WithPEC() Option {
+	return func(v *I2C) error {
+		v.pec = true
+		return nil
+	}
+}
+
+// WithLogLevel set verbosity of log output of this connection: its
+// messages more verbose than level are suppressed, while other
+// connections aren't affected. Package level (see SetLogLevel)
+// still applies, so connection can't be more verbose than package.
+// QuietLevel make connection quiet.
+func WithLogLevel(level LogLevel) Option {
+	return func(v *I2C) error {
+		v.log = connLogger{set: true, level: level}
+		return nil
+	}
+}
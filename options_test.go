@@ -0,0 +1,99 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithDryRun(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x40)
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	v, err := NewI2C(0x40, 1, WithDryRun([]byte{0xAB, 0xCD}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if err := v.WriteRegU8(0x01, 0x02); err != nil {
+		t.Errorf("write in dry-run: %v", err)
+	}
+	buf, _, err := v.ReadRegBytes(0x01, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, []byte{0xAB, 0xCD, 0xAB}) {
+		t.Errorf("read [% X], want pattern [AB CD AB]", buf)
+	}
+	if a.opens != 0 || len(a.operations()) != 0 {
+		t.Errorf("device accessed in dry-run: %d opens, %+v", a.opens, a.operations())
+	}
+}
+
+func TestDryRunIoctl(t *testing.T) {
+	quietLog(t)
+	v, err := NewI2C(0x40, 1, WithDryRun(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	calls := map[string]func() error{
+		"Probe": func() error {
+			_, err := v.Probe()
+			return err
+		},
+		"Functions": func() error {
+			_, err := v.Functions()
+			return err
+		},
+		"SetTimeout": func() error { return v.SetTimeout(time.Second) },
+		"ReadRegBlock": func() error {
+			_, err := v.ReadRegBlock(0x01)
+			return err
+		},
+		"WriteRead": func() error {
+			_, err := v.WriteRead([]byte{0x01}, make([]byte, 2))
+			return err
+		},
+		"Transfer": func() error {
+			return v.Transfer([]Message{{Addr: 0x40, Data: []byte{0x01}}})
+		},
+		"Exec":             func() error { return v.Exec(NewTransaction().Write([]byte{0x01}, 0)) },
+		"WakeUp":           v.WakeUp,
+		"SetKernelRetries": func() error { return v.SetKernelRetries(3) },
+		"ReadRegBytesRDWR": func() error {
+			_, _, err := v.ReadRegBytesRDWR(0x01, 2)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrDryRun) {
+			t.Errorf("%s in dry-run: %v, want ErrDryRun", name, err)
+		}
+	}
+}
+
+func TestMinReadInterval(t *testing.T) {
+	v, _, clock := newFake(t, 0x40, WithMinReadInterval(10*time.Millisecond))
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 0 {
+		t.Errorf("first read delayed by %v", slept)
+	}
+	clock.Advance(3 * time.Millisecond)
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 7*time.Millisecond {
+		t.Errorf("too fast read delayed by %v, want [7ms]", slept)
+	}
+	clock.Advance(20 * time.Millisecond)
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 1 {
+		t.Errorf("late read delayed: %v", slept)
+	}
+}
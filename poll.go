@@ -0,0 +1,97 @@
+package i2c
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReadRegNonZero reads byte from I2C-device register specified in reg
+// repeatedly, until nonzero value appears, making up to maxTries
+// attempts with delay pause between them. Returns ErrTimeout, if all
+// attempts read zero. Useful for status and ready registers, which
+// transiently read 0 right after command.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegNonZero(reg byte, maxTries int, delay time.Duration) (byte, error) {
+	return v.ReadRegNonZeroContext(context.Background(), reg, maxTries, delay)
+}
+
+// ReadRegNonZeroContext is a variant of ReadRegNonZero, which stops
+// polling with ctx.Err(), when ctx is canceled or its deadline passes.
+func (v *I2C) ReadRegNonZeroContext(ctx context.Context, reg byte, maxTries int,
+	delay time.Duration) (byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i := 0; i < maxTries; i++ {
+		if i > 0 {
+			if err := sleepContext(ctx, v.clock, delay); err != nil {
+				return 0, err
+			}
+		}
+		b, err := v.readRegU8(reg)
+		if err != nil {
+			return 0, err
+		}
+		if b != 0 {
+			return b, nil
+		}
+	}
+	v.log.Debugf("Reg 0x%0X still zero after %d tries", reg, maxTries)
+	return 0, ErrTimeout
+}
+
+// bitPollInterval is a pause between register reads,
+// while waiting for bit to settle.
+const bitPollInterval = 5 * time.Millisecond
+
+// WriteRegBitAndConfirm sets (want is true) or clears bit number bit
+// (0..7) of I2C-device register reg, preserving other bits, and then
+// polls register until bit reads back in requested state, or
+// returns ErrTimeout, if it doesn't happen within timeout.
+// Useful for configuration bits, taking effect asynchronously.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegBitAndConfirm(reg byte, bit uint, want bool, timeout time.Duration) error {
+	return v.WriteRegBitAndConfirmContext(context.Background(), reg, bit, want, timeout)
+}
+
+// WriteRegBitAndConfirmContext is a variant of WriteRegBitAndConfirm,
+// which stops polling with ctx.Err(), when ctx is canceled
+// or its deadline passes before timeout.
+func (v *I2C) WriteRegBitAndConfirmContext(ctx context.Context, reg byte, bit uint,
+	want bool, timeout time.Duration) error {
+	if bit > 7 {
+		return fmt.Errorf("bit %d out of range 0..7", bit)
+	}
+	mask := byte(1) << bit
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	b, err := v.readRegU8(reg)
+	if err != nil {
+		return err
+	}
+	if want {
+		b |= mask
+	} else {
+		b &^= mask
+	}
+	if err := v.writeRegU8(reg, b); err != nil {
+		return err
+	}
+	deadline := v.clock.Now().Add(timeout)
+	for {
+		b, err := v.readRegU8(reg)
+		if err != nil {
+			return err
+		}
+		if (b&mask != 0) == want {
+			return nil
+		}
+		if !v.clock.Now().Before(deadline) {
+			v.log.Debugf("Bit %d of reg 0x%0X not settled within %v", bit, reg, timeout)
+			return ErrTimeout
+		}
+		if err := sleepContext(ctx, v.clock, bitPollInterval); err != nil {
+			return err
+		}
+	}
+}
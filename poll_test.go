@@ -0,0 +1,108 @@
+package i2c
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadRegNonZero(t *testing.T) {
+	v, a, clock := newFake(t, 0x40)
+	c := a.chip(0x40)
+	reads := 0
+	c.onRead = func(c *fakeChip) {
+		reads++
+		if reads == 3 {
+			c.regs[0x07] = 0x81
+		}
+	}
+	b, err := v.ReadRegNonZero(0x07, 5, 10*time.Millisecond)
+	if err != nil || b != 0x81 {
+		t.Fatalf("ReadRegNonZero = 0x%02X, %v", b, err)
+	}
+	if slept := clock.Slept(); len(slept) != 2 || slept[0] != 10*time.Millisecond {
+		t.Errorf("delays between attempts %v, want 2 of 10ms", slept)
+	}
+
+	c.onRead = nil
+	c.regs[0x07] = 0
+	if _, err := v.ReadRegNonZero(0x07, 3, time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestWriteRegBitAndConfirm(t *testing.T) {
+	v, a, clock := newFake(t, 0x40)
+	c := a.chip(0x40)
+	c.regs[0x20] = 0x01
+	// Device accepts write, but bit 3 reads back set
+	// only from the 4th poll after write.
+	polls := 0
+	c.onWrite = func(c *fakeChip) {
+		if c.regs[0x20]&0x08 != 0 {
+			c.regs[0x20] &^= 0x08
+			polls = 0
+		}
+	}
+	c.onRead = func(c *fakeChip) {
+		polls++
+		if polls == 4 {
+			c.regs[0x20] |= 0x08
+		}
+	}
+	if err := v.WriteRegBitAndConfirm(0x20, 3, true, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if c.regs[0x20] != 0x09 {
+		t.Errorf("reg value 0x%02X, want 0x09", c.regs[0x20])
+	}
+	if slept := clock.Slept(); len(slept) != 3 || slept[0] != bitPollInterval {
+		t.Errorf("delays between polls %v, want 3 of %v", slept, bitPollInterval)
+	}
+
+	c.onWrite, c.onRead = nil, nil
+	c.regs[0x20] = 0x00
+	// Bit never clears, since device keeps it set.
+	c.onRead = func(c *fakeChip) { c.regs[0x20] = 0x08 }
+	err := v.WriteRegBitAndConfirm(0x20, 3, false, 50*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestCompoundOpCutByContext(t *testing.T) {
+	v, a, clock := newFake(t, 0x40)
+	c := a.chip(0x40)
+	parent, expire := context.WithCancel(context.Background())
+	ctx, cancel := context.WithTimeout(parent, time.Hour)
+	defer cancel()
+	// Parent context expires at 3rd poll, while bit never settles.
+	reads := 0
+	c.onRead = func(c *fakeChip) {
+		c.regs[0x20] = 0x00
+		reads++
+		if reads == 4 {
+			expire()
+		}
+	}
+	err := v.WriteRegBitAndConfirmContext(ctx, 0x20, 0, true, time.Hour)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	// Read-modify-write read and 3 polls.
+	if reads != 4 {
+		t.Errorf("%d reads, want 4", reads)
+	}
+	if slept := clock.Slept(); len(slept) != 2 {
+		t.Errorf("delays %v, want 2 polls delays", slept)
+	}
+
+	// Expired context stops polling before the next attempt.
+	if _, err := v.ReadRegNonZeroContext(ctx, 0x20, 10, time.Millisecond); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if reads != 5 {
+		t.Errorf("%d reads, want 5", reads)
+	}
+}
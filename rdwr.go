@@ -0,0 +1,220 @@
+package i2c
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// txMsg is a single segment of combined transaction.
+type txMsg struct {
+	flags uint16
+	buf   []byte
+}
+
+// Transaction accumulate messages (write and read segments), which
+// are sent to I2C-device by Exec as one combined I2C_RDWR transfer:
+// with repeated START between segments and single STOP at the end,
+// so no other bus master may intervene.
+//
+// Each segment may carry additional I2C_M_... flags to build exotic
+// transactions: I2C_M_TEN (10-bit address), I2C_M_NOSTART (gang
+// reads/writes without repeated START), I2C_M_IGNORE_NAK and
+// I2C_M_NO_RD_ACK. Adapter must support corresponding functionality.
+type Transaction struct {
+	msgs []txMsg
+}
+
+// NewTransaction create empty combined transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Write append write segment, sending data to I2C-device,
+// with additional message flags.
+func (t *Transaction) Write(data []byte, flags uint16) *Transaction {
+	t.msgs = append(t.msgs, txMsg{flags: flags &^ I2C_M_RD, buf: data})
+	return t
+}
+
+// Read append read segment, receiving len(buf) bytes
+// from I2C-device to buf, with additional message flags.
+func (t *Transaction) Read(buf []byte, flags uint16) *Transaction {
+	t.msgs = append(t.msgs, txMsg{flags: flags | I2C_M_RD, buf: buf})
+	return t
+}
+
+// Message is a single message (segment) of I2C_RDWR transfer:
+// data written to or read from (if Flags contain I2C_M_RD)
+// I2C-device at address Addr, with I2C_M_... flags.
+type Message struct {
+	Addr  uint16
+	Flags uint16
+	Data  []byte
+}
+
+// messages convert transaction segments
+// to messages addressed to addr.
+func (t *Transaction) messages(addr uint16, flags uint16) []Message {
+	msgs := make([]Message, len(t.msgs))
+	for i, m := range t.msgs {
+		msgs[i] = Message{Addr: addr, Flags: m.flags | flags, Data: m.buf}
+	}
+	return msgs
+}
+
+// marshal convert messages to kernel i2c_msg structures.
+func marshal(msgs []Message) []i2cMsg {
+	return marshalTo(make([]i2cMsg, len(msgs)), msgs)
+}
+
+// marshalTo is marshal, which fill kmsgs (of at least len(msgs)
+// length) instead of new slice.
+func marshalTo(kmsgs []i2cMsg, msgs []Message) []i2cMsg {
+	kmsgs = kmsgs[:len(msgs)]
+	for i, m := range msgs {
+		kmsgs[i] = i2cMsg{addr: m.Addr, flags: m.Flags, len: uint16(len(m.Data))}
+		if len(m.Data) > 0 {
+			kmsgs[i].buf = &m.Data[0]
+		}
+	}
+	return kmsgs
+}
+
+// Transfer send messages msgs in one I2C_RDWR call: with repeated
+// START between messages and single STOP at the end. Messages may be
+// addressed to different devices on the bus. Kernel limits number
+// of messages to I2C_RDWR_IOCTL_MAX_MSGS (42). Like raw transfers,
+// Transfer doesn't acquire connection lock.
+// Adapter must support I2C_FUNC_I2C functionality.
+func (v *I2C) Transfer(msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	if len(msgs) > I2C_RDWR_IOCTL_MAX_MSGS {
+		return fmt.Errorf("%d messages exceed maximum of %d per transfer",
+			len(msgs), I2C_RDWR_IOCTL_MAX_MSGS)
+	}
+	for _, m := range msgs {
+		if len(m.Data) > 0xFFFF {
+			return fmt.Errorf("message length %d exceed %d bytes", len(m.Data), 0xFFFF)
+		}
+	}
+	if err := v.transfer(msgs); err != nil {
+		return v.opError("transfer", err)
+	}
+	return nil
+}
+
+// transfer is Transfer of validated messages, returning bare ioctl error.
+func (v *I2C) transfer(msgs []Message) error {
+	if v.limit != nil {
+		n := 0
+		for _, m := range msgs {
+			n += len(m.Data)
+		}
+		v.pace(n)
+	}
+	kmsgs := marshal(msgs)
+	data := i2cRdwrIoctlData{msgs: &kmsgs[0], nmsgs: uint32(len(kmsgs))}
+	v.log.Debugf("Transfer %d messages", len(kmsgs))
+	err := devIoctlPtr(v.rc, I2C_RDWR, unsafe.Pointer(&data))
+	runtime.KeepAlive(msgs)
+	return err
+}
+
+// Exec send transaction to I2C-device with I2C_RDWR ioctl.
+// Adapter must support I2C_FUNC_I2C functionality.
+func (v *I2C) Exec(t *Transaction) error {
+	return v.Transfer(v.txMessages(t))
+}
+
+// txMessages convert transaction to messages addressed to I2C-device.
+func (v *I2C) txMessages(t *Transaction) []Message {
+	var flags uint16
+	if v.tenBit {
+		flags = I2C_M_TEN
+	}
+	return t.messages(v.GetAddr16(), flags)
+}
+
+// readRegRDWR reads len(buf) bytes from I2C-device starting from
+// reg address in one combined transaction: register address write
+// and data read are separated by repeated START, without STOP.
+func (v *I2C) readRegRDWR(tx uint64, reg byte, buf []byte) (int, error) {
+	v.regBuf[0] = reg
+	return v.readCombined(tx, v.regBuf[:], buf)
+}
+
+// readCombined writes register address addr and reads len(buf) bytes
+// from I2C-device in one combined transaction. Like separate reads,
+// it is subject to auto wake-up, minimum read interval, reconnect
+// and retries. Kernel messages are kept in connection, so no memory
+// allocated in steady state. Connection lock must be held.
+func (v *I2C) readCombined(tx uint64, addr []byte, buf []byte) (int, error) {
+	var flags uint16
+	if v.tenBit {
+		flags = I2C_M_TEN
+	}
+	msgs := []Message{
+		{Addr: v.GetAddr16(), Flags: flags, Data: addr},
+		{Addr: v.GetAddr16(), Flags: flags | I2C_M_RD, Data: buf},
+	}
+	v.pace(len(addr) + len(buf))
+	n, err := v.doRead(func() (int, error) {
+		kmsgs := marshalTo(v.combMsgs[:], msgs)
+		v.combData = i2cRdwrIoctlData{msgs: &kmsgs[0], nmsgs: uint32(len(kmsgs))}
+		if err := devIoctlPtr(v.rc, I2C_RDWR, unsafe.Pointer(&v.combData)); err != nil {
+			return 0, err
+		}
+		return len(buf), nil
+	})
+	if err != nil {
+		return n, err
+	}
+	if v.log.enabled(DebugLevel) {
+		v.log.Debugf("[tx %d] Read %d hex bytes from reg 0x%s: [%+v]",
+			tx, len(buf), hex.EncodeToString(addr), hex.EncodeToString(buf))
+	}
+	return n, nil
+}
+
+// ReadRegBytesRDWR read count of n byte's sequence from I2C-device
+// starting from reg address in one combined I2C_RDWR transaction.
+// Since kernel performs register address write and data read with
+// repeated START and no STOP in between, operation is atomic on
+// driver level and safe against concurrent callers, talking to other
+// devices. Adapter must support I2C_FUNC_I2C functionality.
+// Use WithCombinedReads to route all ReadReg... helpers this way.
+func (v *I2C) ReadRegBytesRDWR(reg byte, n int) ([]byte, int, error) {
+	tx := nextTx()
+	buf := make([]byte, n)
+	c, err := v.readRegRDWR(tx, reg, buf)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, c, nil
+}
+
+// WriteRead sends w to I2C-device and then reads len(r) bytes to r
+// in one combined I2C_RDWR transaction with repeated START between
+// phases. Unlike ReadRegBytes, write phase may carry any number
+// of bytes (command with arguments, for instance), which suits
+// devices not following single register pointer model (SHT3x, ADCs).
+// Returns number of bytes read to r.
+// Adapter must support I2C_FUNC_I2C functionality.
+func (v *I2C) WriteRead(w []byte, r []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	v.log.Debugf("[tx %d] Write %d hex bytes: [%+v], then read %d bytes",
+		tx, len(w), hex.EncodeToString(w), len(r))
+	t := NewTransaction().Write(w, 0).Read(r, 0)
+	if err := v.Exec(t); err != nil {
+		v.setReadError(err)
+		return 0, err
+	}
+	v.log.Debugf("[tx %d] Read %d hex bytes: [%+v]", tx, len(r), hex.EncodeToString(r))
+	return len(r), nil
+}
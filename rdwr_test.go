@@ -0,0 +1,228 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestTransactionFlagsMarshal(t *testing.T) {
+	w := []byte{0x10}
+	r1 := make([]byte, 2)
+	r2 := make([]byte, 3)
+	tr := NewTransaction().
+		Write(w, I2C_M_IGNORE_NAK|I2C_M_RD).
+		Read(r1, I2C_M_NO_RD_ACK).
+		Read(r2, I2C_M_NOSTART)
+	kmsgs := marshal(tr.messages(0x3A5, I2C_M_TEN))
+	want := []struct {
+		flags uint16
+		len   uint16
+		buf   *byte
+	}{
+		// I2C_M_RD is dropped from write segment.
+		{I2C_M_TEN | I2C_M_IGNORE_NAK, 1, &w[0]},
+		{I2C_M_TEN | I2C_M_RD | I2C_M_NO_RD_ACK, 2, &r1[0]},
+		{I2C_M_TEN | I2C_M_RD | I2C_M_NOSTART, 3, &r2[0]},
+	}
+	if len(kmsgs) != len(want) {
+		t.Fatalf("%d messages marshaled, want %d", len(kmsgs), len(want))
+	}
+	for i, m := range kmsgs {
+		if m.addr != 0x3A5 || m.flags != want[i].flags || m.len != want[i].len ||
+			m.buf != want[i].buf {
+			t.Errorf("message %d: %+v, want addr 0x3A5 %+v", i, m, want[i])
+		}
+	}
+}
+
+func TestExecFlags(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	copy(a.chip(0x40).regs[0x10:], []byte{0x01, 0x02, 0x03})
+	r1 := make([]byte, 1)
+	r2 := make([]byte, 2)
+	tr := NewTransaction().Write([]byte{0x10}, 0).Read(r1, 0).Read(r2, I2C_M_NOSTART)
+	if err := v.Exec(tr); err != nil {
+		t.Fatal(err)
+	}
+	if r1[0] != 0x01 || r2[0] != 0x02 || r2[1] != 0x03 {
+		t.Errorf("gang read returned [% X] [% X]", r1, r2)
+	}
+	ops := a.operations("read", "write")
+	if len(ops) != 3 || ops[2].flags != I2C_M_RD|I2C_M_NOSTART {
+		t.Errorf("unexpected messages: %+v", ops)
+	}
+}
+
+func TestCombinedReadPipeline(t *testing.T) {
+	v, a, clock := newFake(t, 0x50, WithCombinedReads(),
+		WithMinReadInterval(10*time.Millisecond), WithRegAddrOrder(binary.BigEndian))
+	c := a.chip(0x50)
+	c.regs[0x10] = 0xA5
+	v.SetRetries(2, time.Millisecond)
+
+	// Transient failure is retried as whole combined transaction.
+	a.failNext(syscall.EIO)
+	if b, err := v.ReadRegU8(0x10); err != nil || b != 0xA5 {
+		t.Fatalf("ReadRegU8 = 0x%02X, %v", b, err)
+	}
+	ops := a.operations("read", "write")
+	if len(ops) != 2 || !ops[0].rdwr || !ops[1].rdwr {
+		t.Errorf("expected one combined transaction, got %+v", ops)
+	}
+	// Too fast second read is paced.
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if slept := clock.Slept(); len(slept) != 2 || slept[0] != time.Millisecond ||
+		slept[1] != 10*time.Millisecond {
+		t.Errorf("delays %v, want [1ms 10ms]", slept)
+	}
+
+	// 16-bit addressed read goes the same way.
+	c.addrLen = 2
+	copy(c.regs[0x1234:], []byte{0x01, 0x02})
+	a.reset()
+	a.failNext(syscall.EIO)
+	buf, _, err := v.ReadRegBytes16(0x1234, 2)
+	if err != nil || !bytes.Equal(buf, []byte{0x01, 0x02}) {
+		t.Fatalf("ReadRegBytes16 = [% X], %v", buf, err)
+	}
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x12, 0x34}) {
+		t.Errorf("address writes %X, want [[12 34]]", w)
+	}
+}
+
+func TestCombinedReadTurnaround(t *testing.T) {
+	v, a, clock := newFake(t, 0x50, WithCombinedReads(), WithTurnaroundDelay(time.Millisecond))
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	// Turnaround delay make read separate.
+	for _, op := range a.operations("read", "write") {
+		if op.rdwr {
+			t.Errorf("combined transaction used: %+v", op)
+		}
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != time.Millisecond {
+		t.Errorf("delays %v, want [1ms]", slept)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	v, a, _ := newFake(t, 0x44)
+	c := a.chip(0x44)
+	// Command 0x2400 (SHT3x single shot) moves pointer to 0x2400.
+	c.addrLen = 2
+	copy(c.regs[0x2400:], []byte{0x66, 0x77, 0x88})
+	r := make([]byte, 3)
+	n, err := v.WriteRead([]byte{0x24, 0x00}, r)
+	if err != nil || n != 3 || !bytes.Equal(r, []byte{0x66, 0x77, 0x88}) {
+		t.Fatalf("WriteRead = %d, [% X], %v", n, r, err)
+	}
+	ops := a.operations("read", "write")
+	if len(ops) != 2 {
+		t.Fatalf("expected 2 messages, got %+v", ops)
+	}
+	if w := ops[0]; w.kind != "write" || !w.rdwr || w.flags&I2C_M_RD != 0 ||
+		!bytes.Equal(w.data, []byte{0x24, 0x00}) {
+		t.Errorf("write message %+v", w)
+	}
+	if rd := ops[1]; rd.kind != "read" || !rd.rdwr || rd.flags&I2C_M_RD == 0 || len(rd.data) != 3 {
+		t.Errorf("read message %+v", rd)
+	}
+}
+
+func TestTransferLayout(t *testing.T) {
+	if o := [3]uintptr{unsafe.Offsetof(i2cMsg{}.addr), unsafe.Offsetof(i2cMsg{}.flags),
+		unsafe.Offsetof(i2cMsg{}.len)}; o != [3]uintptr{0, 2, 4} {
+		t.Errorf("addr, flags, len offsets %v, want [0 2 4]", o)
+	}
+	if o := unsafe.Offsetof(i2cRdwrIoctlData{}.nmsgs); o != unsafe.Sizeof(uintptr(0)) {
+		t.Errorf("nmsgs offset %d, want %d", o, unsafe.Sizeof(uintptr(0)))
+	}
+	w := []byte{0x12, 0x34}
+	r := make([]byte, 300)
+	kmsgs := marshal([]Message{
+		{Addr: 0x50, Data: w},
+		{Addr: 0x3A5, Flags: I2C_M_TEN | I2C_M_RD, Data: r},
+		{Addr: 0x51},
+	})
+	want := []struct {
+		addr, flags, len uint16
+		buf              uintptr
+	}{
+		{0x50, 0, 2, uintptr(unsafe.Pointer(&w[0]))},
+		{0x3A5, I2C_M_TEN | I2C_M_RD, 300, uintptr(unsafe.Pointer(&r[0]))},
+		{0x51, 0, 0, 0},
+	}
+	// Messages are packed back to back, as C array of struct i2c_msg.
+	const size = unsafe.Sizeof(i2cMsg{})
+	base := unsafe.Pointer(&kmsgs[0])
+	for i, m := range want {
+		raw := (*[size]byte)(unsafe.Add(base, uintptr(i)*size))[:]
+		var buf uintptr
+		if unsafe.Sizeof(buf) == 8 {
+			buf = uintptr(binary.NativeEndian.Uint64(raw[unsafe.Offsetof(i2cMsg{}.buf):]))
+		} else {
+			buf = uintptr(binary.NativeEndian.Uint32(raw[unsafe.Offsetof(i2cMsg{}.buf):]))
+		}
+		if got := binary.NativeEndian.Uint16(raw[0:]); got != m.addr {
+			t.Errorf("message %d: addr 0x%X, want 0x%X", i, got, m.addr)
+		}
+		if got := binary.NativeEndian.Uint16(raw[2:]); got != m.flags {
+			t.Errorf("message %d: flags 0x%X, want 0x%X", i, got, m.flags)
+		}
+		if got := binary.NativeEndian.Uint16(raw[4:]); got != m.len {
+			t.Errorf("message %d: len %d, want %d", i, got, m.len)
+		}
+		if buf != m.buf {
+			t.Errorf("message %d: buf 0x%X, want 0x%X", i, buf, m.buf)
+		}
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	v, a, _ := newFake(t, 0x50)
+	copy(a.chip(0x51).regs[0x20:], []byte{0xAA, 0xBB})
+	r := make([]byte, 2)
+	err := v.Transfer([]Message{
+		{Addr: 0x50, Data: []byte{0x10, 0x01}},
+		{Addr: 0x51, Data: []byte{0x20}},
+		{Addr: 0x51, Flags: I2C_M_RD, Data: r},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{0xAA, 0xBB}) || a.chip(0x50).regs[0x10] != 0x01 {
+		t.Errorf("read [% X], register 0x%02X", r, a.chip(0x50).regs[0x10])
+	}
+	ops := a.operations()
+	if len(ops) != 3 || !ops[0].rdwr || ops[1].addr != 0x51 || ops[2].kind != "read" {
+		t.Errorf("operations %+v", ops)
+	}
+
+	a.reset()
+	if err := v.Transfer(nil); err != nil {
+		t.Errorf("empty transfer: %v", err)
+	}
+	msgs := make([]Message, I2C_RDWR_IOCTL_MAX_MSGS+1)
+	for i := range msgs {
+		msgs[i] = Message{Addr: 0x50, Data: []byte{0x00}}
+	}
+	if err := v.Transfer(msgs); err == nil {
+		t.Error("43 messages accepted")
+	}
+	if err := v.Transfer([]Message{{Addr: 0x50, Data: make([]byte, 0x10000)}}); err == nil {
+		t.Error("64 KiB message accepted")
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("rejected transfers reached bus: %+v", ops)
+	}
+	if err := v.Transfer(msgs[:I2C_RDWR_IOCTL_MAX_MSGS]); err != nil {
+		t.Errorf("42 messages: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"syscall"
+	"time"
+)
+
+// maxReconnectAttempts limit number of attempts
+// to reopen I2C-connection after device removal.
+const maxReconnectAttempts = 10
+
+// backoff describe exponential delay growth between attempts.
+type backoff struct {
+	base   time.Duration
+	max    time.Duration
+	factor float64
+}
+
+// delay return pause before attempt (counting from 0):
+// base*factor^attempt, capped by max, with "equal jitter"
+// applied, so result is randomized in range [d/2, d).
+func (b *backoff) delay(attempt int) time.Duration {
+	d := float64(b.base) * math.Pow(b.factor, float64(attempt))
+	if d > float64(b.max) {
+		d = float64(b.max)
+	}
+	half := time.Duration(d / 2)
+	if half <= 0 {
+		return time.Duration(d)
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// WithReconnectBackoff enable automatic reconnect, when I2C-connection
+// is lost (read or write fail with ENODEV, as happens when USB-I2C bridge
+// re-enumerates). Device file is reopened with exponentially growing
+// delay between attempts, starting from base, multiplied by factor
+// each time and capped by max, with random jitter applied, so
+// disconnected bridge isn't hammered. Failed operation
+// is repeated once after successful reconnect.
+func WithReconnectBackoff(base, max time.Duration, factor float64) Option {
+	return func(v *I2C) error {
+		if base <= 0 || max < base || factor < 1 {
+			return fmt.Errorf("invalid reconnect backoff: base=%v, max=%v, factor=%v",
+				base, max, factor)
+		}
+		v.reconnect = &backoff{base: base, max: max, factor: factor}
+		return nil
+	}
+}
+
+// reopen open device file again, restoring device address, and
+// replace current one with it. Current device file is closed only
+// after new one is bound, so on failure connection keeps it
+// (transfers keep failing with original error, but never find
+// connection without device file).
+func (v *I2C) reopen() error {
+	f, err := openDevice(v.devPath())
+	if err != nil {
+		return err
+	}
+	if err := v.bind(f); err != nil {
+		f.Close()
+		return err
+	}
+	if v.rc != nil {
+		v.rc.Close()
+	}
+	v.rc = f
+	return nil
+}
+
+// Reopen close current device file (if any) and open bus again,
+// restoring addressing mode and slave address, so connection remains
+// usable after adapter has been re-enumerated (all transfers fail
+// with ENODEV). Configuration of connection is preserved. If bus can't
+// be opened again, error is returned and current device file is kept,
+// so connection may be reopened later or closed as usual.
+// See WithReconnectBackoff for automatic reconnect.
+func (v *I2C) Reopen() error {
+	if v.dryRun {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.log.Infof("Reopen connection to bus %d, address 0x%0X", v.bus, v.GetAddr16())
+	return v.reopen()
+}
+
+// tryReconnect reopen I2C-connection after err, if automatic
+// reconnect is enabled and err signals device removal.
+// Returns true if connection restored.
+func (v *I2C) tryReconnect(err error) bool {
+	if v.reconnect == nil || !errors.Is(err, syscall.ENODEV) {
+		return false
+	}
+	for attempt := 0; attempt < maxReconnectAttempts; attempt++ {
+		d := v.reconnect.delay(attempt)
+		v.log.Infof("Connection to bus %d lost, reconnect in %v...", v.bus, d)
+		v.clock.Sleep(d)
+		rerr := v.reopen()
+		if rerr == nil {
+			v.log.Infof("Connection to bus %d restored", v.bus)
+			return true
+		}
+		v.log.Debugf("Reconnect attempt %d failed: %v", attempt+1, rerr)
+	}
+	return false
+}
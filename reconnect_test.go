@@ -0,0 +1,165 @@
+package i2c
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := &backoff{base: 10 * time.Millisecond, max: 40 * time.Millisecond, factor: 2}
+	nominal := []time.Duration{10, 20, 40, 40, 40}
+	for attempt, n := range nominal {
+		n *= time.Millisecond
+		for i := 0; i < 100; i++ {
+			if d := b.delay(attempt); d < n/2 || d >= n {
+				t.Fatalf("attempt %d: delay %v out of [%v, %v)", attempt, d, n/2, n)
+			}
+		}
+	}
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	v, a, clock := newFake(t, 0x40,
+		WithReconnectBackoff(10*time.Millisecond, 40*time.Millisecond, 2))
+	a.chip(0x40).regs[0x01] = 0x77
+	// Adapter is gone for three reopen attempts.
+	open := openDevice
+	failures := 3
+	openDevice = func(path string) (device, error) {
+		if failures > 0 {
+			failures--
+			return nil, syscall.ENOENT
+		}
+		return open(path)
+	}
+	a.failNext(syscall.ENODEV)
+	b, err := v.ReadRegU8(0x01)
+	if err != nil || b != 0x77 {
+		t.Fatalf("ReadRegU8 after reconnect: 0x%02X, %v", b, err)
+	}
+	slept := clock.Slept()
+	bounds := [][2]time.Duration{{5, 10}, {10, 20}, {20, 40}, {20, 40}}
+	if len(slept) != len(bounds) {
+		t.Fatalf("delays %v, want %d of them", slept, len(bounds))
+	}
+	for i, d := range slept {
+		lo, hi := bounds[i][0]*time.Millisecond, bounds[i][1]*time.Millisecond
+		if d < lo || d >= hi {
+			t.Errorf("delay %d: %v out of [%v, %v)", i, d, lo, hi)
+		}
+	}
+}
+
+func TestReconnectFailed(t *testing.T) {
+	v, a, _ := newFake(t, 0x40,
+		WithReconnectBackoff(10*time.Millisecond, 40*time.Millisecond, 2))
+	quietLog(t)
+	a.chip(0x40).regs[0x01] = 0x77
+	// Adapter never comes back.
+	open := openDevice
+	openDevice = func(path string) (device, error) {
+		return nil, syscall.ENOENT
+	}
+	a.failNext(syscall.ENODEV)
+	if _, err := v.ReadRegU8(0x01); !errors.Is(err, syscall.ENODEV) {
+		t.Fatalf("read after failed reconnect: %v", err)
+	}
+	// Connection keeps old descriptor: next calls fail
+	// with error (or succeed), but don't panic.
+	a.failNext(syscall.ENODEV)
+	if _, err := v.ReadRegU8(0x01); !errors.Is(err, syscall.ENODEV) {
+		t.Fatalf("second read after failed reconnect: %v", err)
+	}
+	openDevice = open
+	if b, err := v.ReadRegU8(0x01); err != nil || b != 0x77 {
+		t.Fatalf("read on kept descriptor: 0x%02X, %v", b, err)
+	}
+	if err := v.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x2A5).regs[0x01] = 0x77
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	quietLog(t)
+	v, err := NewTenBit(0x2A5, 1, WithPEC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	old := v.rc.(*fakeFile)
+
+	// Adapter re-enumerated: descriptor is dead.
+	a.failNext(syscall.ENODEV)
+	if _, err := v.ReadRegU8(0x01); !errors.Is(err, syscall.ENODEV) {
+		t.Fatalf("read on removed adapter: %v", err)
+	}
+	a.reset()
+	if err := v.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if !old.closed {
+		t.Error("old descriptor left open")
+	}
+	if a.opens != 2 {
+		t.Errorf("device opened %d times, want 2", a.opens)
+	}
+	// Addressing mode, PEC and address are issued again, in order.
+	ops := a.operations("ioctl")
+	want := []fakeOp{{cmd: I2C_TENBIT, arg: 1}, {cmd: I2C_PEC, arg: 1}, {cmd: I2C_SLAVE, arg: 0x2A5}}
+	if len(ops) != len(want) {
+		t.Fatalf("ioctls %+v, want %+v", ops, want)
+	}
+	for i := range want {
+		if ops[i].cmd != want[i].cmd || ops[i].arg != want[i].arg {
+			t.Errorf("ioctl %d: 0x%04X(0x%X), want 0x%04X(0x%X)",
+				i, ops[i].cmd, ops[i].arg, want[i].cmd, want[i].arg)
+		}
+	}
+	if f := v.rc.(*fakeFile); !f.tenBit || !f.pec || f.addr != 0x2A5 {
+		t.Errorf("new descriptor state %+v", f)
+	}
+	if v.GetAddr16() != 0x2A5 || v.GetBus() != 1 {
+		t.Errorf("connection moved to bus %d, address 0x%X", v.GetBus(), v.GetAddr16())
+	}
+	if b, err := v.ReadRegU8(0x01); err != nil || b != 0x77 {
+		t.Errorf("ReadRegU8 after Reopen = 0x%02X, %v", b, err)
+	}
+}
+
+func TestReopenFailed(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	quietLog(t)
+	a.chip(0x40).regs[0x01] = 0x77
+	open := openDevice
+	openDevice = func(path string) (device, error) {
+		return nil, syscall.ENOENT
+	}
+	if err := v.Reopen(); !errors.Is(err, syscall.ENOENT) {
+		t.Fatalf("Reopen of missing bus: %v", err)
+	}
+	// Connection keeps working on current descriptor.
+	if b, err := v.ReadRegU8(0x01); err != nil || b != 0x77 {
+		t.Fatalf("ReadRegU8 after failed Reopen = 0x%02X, %v", b, err)
+	}
+	openDevice = open
+	// Address claimed meanwhile: new descriptor can't be bound.
+	a.busy[0x40] = true
+	if err := v.Reopen(); !errors.Is(err, syscall.EBUSY) {
+		t.Fatalf("Reopen of claimed address: %v", err)
+	}
+	if b, err := v.ReadRegU8(0x01); err != nil || b != 0x77 {
+		t.Fatalf("ReadRegU8 after failed bind = 0x%02X, %v", b, err)
+	}
+	a.busy[0x40] = false
+	if err := v.Reopen(); err != nil {
+		t.Fatalf("Reopen after bus returned: %v", err)
+	}
+	if err := v.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
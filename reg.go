@@ -0,0 +1,92 @@
+//go:build go1.18
+// +build go1.18
+
+package i2c
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// Integer is a set of fixed size integer types,
+// which can be stored in I2C-device registers.
+type Integer interface {
+	~uint8 | ~int8 | ~uint16 | ~int16 | ~uint32 | ~int32 | ~uint64 | ~int64
+}
+
+// Reg is a typed register handle, which binds register address,
+// value type and byte order together, to get clean driver code:
+//
+//	temp := i2c.NewReg[int16](dev, 0xF7, binary.BigEndian)
+//	t, err := temp.Get()
+type Reg[T Integer] struct {
+	i2c   *I2C
+	reg   byte
+	order binary.ByteOrder
+}
+
+// NewReg create typed register handle for register reg
+// of I2C-device with byte order specified in order.
+func NewReg[T Integer](v *I2C, reg byte, order binary.ByteOrder) *Reg[T] {
+	return &Reg[T]{i2c: v, reg: reg, order: order}
+}
+
+// Addr return register address.
+func (r *Reg[T]) Addr() byte {
+	return r.reg
+}
+
+// Get reads register value.
+// SMBus (System Management Bus) protocol over I2C.
+func (r *Reg[T]) Get() (T, error) {
+	var value T
+	size := int(unsafe.Sizeof(value))
+	r.i2c.mu.Lock()
+	defer r.i2c.mu.Unlock()
+	buf, _, err := r.i2c.readRegBytes(r.reg, size)
+	if err != nil {
+		return 0, err
+	}
+	// Sign extension is harmless for unsigned types,
+	// since conversion keeps lower bits only.
+	value = T(signExtend(decodeUint(buf, r.order), uint(size)*8))
+	r.i2c.log.Debugf("Read %T %v from reg 0x%0X", value, value, r.reg)
+	return value, nil
+}
+
+// Set writes value to register. Like WriteRegU8, 8-bit register
+// value is checked against set registered with SetAllowedValues.
+// SMBus (System Management Bus) protocol over I2C.
+func (r *Reg[T]) Set(value T) error {
+	size := int(unsafe.Sizeof(value))
+	r.i2c.mu.Lock()
+	defer r.i2c.mu.Unlock()
+	if size == 1 {
+		if err := r.i2c.checkAllowed(r.reg, byte(value)); err != nil {
+			return err
+		}
+	}
+	if err := r.i2c.writeRegUint(r.reg, size, uint64(value), r.order); err != nil {
+		return err
+	}
+	r.i2c.log.Debugf("Write %T %v to reg 0x%0X", value, value, r.reg)
+	return nil
+}
+
+// ReadRegEnum reads byte from I2C-device register specified in reg
+// directly into custom enum type T, improving type safety of drivers.
+// SMBus (System Management Bus) protocol over I2C.
+func ReadRegEnum[T ~uint8](v *I2C, reg byte) (T, error) {
+	b, err := v.ReadRegU8(reg)
+	if err != nil {
+		return 0, err
+	}
+	return T(b), nil
+}
+
+// WriteRegEnum writes custom enum type value
+// to I2C-device register specified in reg.
+// SMBus (System Management Bus) protocol over I2C.
+func WriteRegEnum[T ~uint8](v *I2C, reg byte, value T) error {
+	return v.WriteRegU8(reg, uint8(value))
+}
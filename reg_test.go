@@ -0,0 +1,92 @@
+//go:build go1.18
+// +build go1.18
+
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestRegTyped(t *testing.T) {
+	v, a, _ := newFake(t, 0x76)
+
+	u8 := NewReg[uint8](v, 0x01, binary.BigEndian)
+	if err := u8.Set(0xA5); err != nil {
+		t.Fatal(err)
+	}
+	s16 := NewReg[int16](v, 0x02, binary.BigEndian)
+	if err := s16.Set(-2); err != nil {
+		t.Fatal(err)
+	}
+	u32 := NewReg[uint32](v, 0x04, binary.LittleEndian)
+	if err := u32.Set(0x01020304); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x01, 0xA5}, {0x02, 0xFF, 0xFE}, {0x04, 0x04, 0x03, 0x02, 0x01}}
+	w := a.writes()
+	for i := range want {
+		if i >= len(w) || !bytes.Equal(w[i], want[i]) {
+			t.Fatalf("writes %X, want %X", w, want)
+		}
+	}
+
+	if got, err := u8.Get(); err != nil || got != 0xA5 {
+		t.Errorf("uint8 Get = 0x%02X, %v", got, err)
+	}
+	if got, err := s16.Get(); err != nil || got != -2 {
+		t.Errorf("int16 Get = %d, %v", got, err)
+	}
+	if got, err := u32.Get(); err != nil || got != 0x01020304 {
+		t.Errorf("uint32 Get = 0x%08X, %v", got, err)
+	}
+}
+
+func TestRegSetChecked(t *testing.T) {
+	v, a, _ := newFake(t, 0x76)
+	v.SetAllowedValues(0xF4, []byte{0x00, 0x03})
+	mode := NewReg[uint8](v, 0xF4, binary.BigEndian)
+	if err := mode.Set(0x02); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Set of not allowed value: %v", err)
+	}
+	if w := a.writes(); len(w) != 0 {
+		t.Errorf("not allowed value written: %X", w)
+	}
+	if err := mode.Set(0x03); err != nil {
+		t.Fatal(err)
+	}
+
+	a.failNext(syscall.EIO)
+	err := NewReg[uint16](v, 0x10, binary.BigEndian).Set(0x1234)
+	var opErr *OpError
+	if !errors.As(err, &opErr) || opErr.Reg != 0x10 || !errors.Is(err, syscall.EIO) {
+		t.Errorf("Set failure: %v", err)
+	}
+}
+
+type powerMode uint8
+
+const (
+	powerSleep powerMode = iota
+	powerForced
+	powerNormal powerMode = 3
+)
+
+func TestRegEnum(t *testing.T) {
+	v, a, _ := newFake(t, 0x76)
+	for _, mode := range []powerMode{powerNormal, powerForced, powerSleep} {
+		if err := WriteRegEnum(v, 0xF4, mode); err != nil {
+			t.Fatal(err)
+		}
+		if b := a.chip(0x76).regs[0xF4]; b != byte(mode) {
+			t.Errorf("register holds 0x%02X, want 0x%02X", b, byte(mode))
+		}
+		got, err := ReadRegEnum[powerMode](v, 0xF4)
+		if err != nil || got != mode {
+			t.Errorf("ReadRegEnum = %v, %v, want %v", got, err, mode)
+		}
+	}
+}
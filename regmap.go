@@ -0,0 +1,155 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// RegisterDesc describe named register of I2C-device.
+type RegisterDesc struct {
+	Name string
+	Reg  byte
+	// Width is a value length in bytes: 1, 2 or 4.
+	Width  int
+	Signed bool
+	// Order is a byte order of multi-byte value,
+	// nil means binary.BigEndian.
+	Order binary.ByteOrder
+}
+
+// RegisterMap let device drivers access registers by names
+// instead of raw addresses: register descriptors are defined once,
+// then reads and writes are dispatched to corresponding
+// ReadReg.../WriteReg... methods of I2C-connection.
+type RegisterMap struct {
+	i2c  *I2C
+	regs map[string]RegisterDesc
+}
+
+// NewRegisterMap create RegisterMap over I2C-connection v
+// with registers regs defined.
+func NewRegisterMap(v *I2C, regs ...RegisterDesc) (*RegisterMap, error) {
+	rm := &RegisterMap{i2c: v, regs: make(map[string]RegisterDesc)}
+	for _, desc := range regs {
+		if err := rm.Define(desc); err != nil {
+			return nil, err
+		}
+	}
+	return rm, nil
+}
+
+// Define add register descriptor to the map,
+// replacing one with the same name.
+func (rm *RegisterMap) Define(desc RegisterDesc) error {
+	switch desc.Width {
+	case 1, 2, 4:
+	default:
+		return fmt.Errorf("register %s: width %d not in 1, 2, 4", desc.Name, desc.Width)
+	}
+	if desc.Order == nil {
+		desc.Order = binary.BigEndian
+	}
+	rm.regs[desc.Name] = desc
+	return nil
+}
+
+// lookup return descriptor of register name,
+// verifying its width, if width is positive.
+func (rm *RegisterMap) lookup(name string, width int) (RegisterDesc, error) {
+	desc, ok := rm.regs[name]
+	if !ok {
+		return desc, fmt.Errorf("register %s not defined", name)
+	}
+	if width > 0 && desc.Width != width {
+		return desc, fmt.Errorf("register %s has width %d, not %d", name, desc.Width, width)
+	}
+	return desc, nil
+}
+
+// Read reads register name and returns its value,
+// sign-extended for signed registers.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) Read(name string) (int64, error) {
+	desc, err := rm.lookup(name, 0)
+	if err != nil {
+		return 0, err
+	}
+	v := rm.i2c
+	v.mu.Lock()
+	u, err := v.readRegUint(desc.Reg, desc.Width, desc.Order)
+	v.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if desc.Signed {
+		return signExtend(u, uint(desc.Width)*8), nil
+	}
+	return int64(u), nil
+}
+
+// ReadU8 reads 1-byte register name.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) ReadU8(name string) (byte, error) {
+	desc, err := rm.lookup(name, 1)
+	if err != nil {
+		return 0, err
+	}
+	return rm.i2c.ReadRegU8(desc.Reg)
+}
+
+// ReadU16 reads 2-byte register name with its byte order.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) ReadU16(name string) (uint16, error) {
+	desc, err := rm.lookup(name, 2)
+	if err != nil {
+		return 0, err
+	}
+	return rm.i2c.ReadRegU16(desc.Reg, desc.Order)
+}
+
+// ReadS16 reads 2-byte signed register name with its byte order.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) ReadS16(name string) (int16, error) {
+	w, err := rm.ReadU16(name)
+	return int16(w), err
+}
+
+// ReadU32 reads 4-byte register name with its byte order.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) ReadU32(name string) (uint32, error) {
+	desc, err := rm.lookup(name, 4)
+	if err != nil {
+		return 0, err
+	}
+	return rm.i2c.ReadRegU32(desc.Reg, desc.Order)
+}
+
+// WriteU8 writes 1-byte register name.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) WriteU8(name string, value byte) error {
+	desc, err := rm.lookup(name, 1)
+	if err != nil {
+		return err
+	}
+	return rm.i2c.WriteRegU8(desc.Reg, value)
+}
+
+// WriteU16 writes 2-byte register name with its byte order.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) WriteU16(name string, value uint16) error {
+	desc, err := rm.lookup(name, 2)
+	if err != nil {
+		return err
+	}
+	return rm.i2c.WriteRegU16(desc.Reg, value, desc.Order)
+}
+
+// WriteU32 writes 4-byte register name with its byte order.
+// SMBus (System Management Bus) protocol over I2C.
+func (rm *RegisterMap) WriteU32(name string, value uint32) error {
+	desc, err := rm.lookup(name, 4)
+	if err != nil {
+		return err
+	}
+	return rm.i2c.WriteRegU32(desc.Reg, value, desc.Order)
+}
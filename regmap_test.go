@@ -0,0 +1,89 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestRegisterMap(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	copy(c.regs[0x00:], []byte{0xFF, 0x38})             // TEMP: S16 BE
+	copy(c.regs[0x02:], []byte{0x34, 0x12})             // CALIB: U16 LE
+	copy(c.regs[0x10:], []byte{0x80})                   // OFFSET: S8
+	copy(c.regs[0x20:], []byte{0x00, 0x01, 0x86, 0xA0}) // COUNTER: U32
+	rm, err := NewRegisterMap(v,
+		RegisterDesc{Name: "TEMP", Reg: 0x00, Width: 2, Signed: true},
+		RegisterDesc{Name: "CALIB", Reg: 0x02, Width: 2, Order: binary.LittleEndian},
+		RegisterDesc{Name: "OFFSET", Reg: 0x10, Width: 1, Signed: true},
+		RegisterDesc{Name: "CONFIG", Reg: 0x11, Width: 1},
+		RegisterDesc{Name: "COUNTER", Reg: 0x20, Width: 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reads := map[string]int64{"TEMP": -200, "CALIB": 0x1234, "OFFSET": -128, "COUNTER": 100000}
+	for name, want := range reads {
+		if got, err := rm.Read(name); err != nil || got != want {
+			t.Errorf("Read(%s) = %d, %v, want %d", name, got, err, want)
+		}
+	}
+	if w, err := rm.ReadU16("CALIB"); err != nil || w != 0x1234 {
+		t.Errorf("ReadU16(CALIB) = 0x%04X, %v", w, err)
+	}
+	if w, err := rm.ReadS16("TEMP"); err != nil || w != -200 {
+		t.Errorf("ReadS16(TEMP) = %d, %v", w, err)
+	}
+	if w, err := rm.ReadU32("COUNTER"); err != nil || w != 100000 {
+		t.Errorf("ReadU32(COUNTER) = %d, %v", w, err)
+	}
+
+	a.reset()
+	if err := rm.WriteU8("CONFIG", 0x5A); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.WriteU16("CALIB", 0xABCD); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.WriteU32("COUNTER", 0x01020304); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x11, 0x5A}, {0x02, 0xCD, 0xAB}, {0x20, 0x01, 0x02, 0x03, 0x04}}
+	w := a.writes()
+	if len(w) != len(want) {
+		t.Fatalf("writes %X, want %X", w, want)
+	}
+	for i := range want {
+		if !bytes.Equal(w[i], want[i]) {
+			t.Errorf("write %d: [% X], want [% X]", i, w[i], want[i])
+		}
+	}
+	if b, err := rm.ReadU8("CONFIG"); err != nil || b != 0x5A {
+		t.Errorf("ReadU8(CONFIG) = 0x%02X, %v", b, err)
+	}
+}
+
+func TestRegisterMapErrors(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	if _, err := NewRegisterMap(v, RegisterDesc{Name: "BAD", Reg: 0x00, Width: 3}); err == nil {
+		t.Error("width 3 accepted")
+	}
+	rm, err := NewRegisterMap(v, RegisterDesc{Name: "CONFIG", Reg: 0x01, Width: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rm.Read("MISSING"); err == nil {
+		t.Error("undefined register read")
+	}
+	if _, err := rm.ReadU16("CONFIG"); err == nil {
+		t.Error("1-byte register read as word")
+	}
+	if err := rm.WriteU32("CONFIG", 0); err == nil {
+		t.Error("1-byte register written as double word")
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("rejected accesses reached bus: %+v", ops)
+	}
+}
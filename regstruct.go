@@ -0,0 +1,184 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Struct field tag used by ReadRegStruct and FormatRegStruct, in format
+//
+//	`i2c:"offset=N[,size=N][,order=be|le]"`
+//
+// where offset is a field position (in bytes) relative to the start
+// register, size is a field length in bytes (by default equal to field
+// type size) and order is a byte order (big endian by default).
+// Fields without tag are skipped. Field type must be one of fixed size
+// integers: uint8, int8, uint16, int16, uint32, int32, uint64, int64.
+const structTag = "i2c"
+
+// regField describe single tagged struct field.
+type regField struct {
+	index  int
+	name   string
+	offset int
+	size   int
+	order  binary.ByteOrder
+}
+
+// parseRegFields collect tagged fields of struct type t.
+func parseRegFields(t reflect.Type) ([]regField, error) {
+	var fields []regField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup(structTag)
+		if !ok {
+			continue
+		}
+		var typeSize int
+		switch sf.Type.Kind() {
+		case reflect.Uint8, reflect.Int8, reflect.Uint16, reflect.Int16,
+			reflect.Uint32, reflect.Int32, reflect.Uint64, reflect.Int64:
+			typeSize = int(sf.Type.Size())
+		default:
+			return nil, fmt.Errorf("field %s: unsupported type %v", sf.Name, sf.Type)
+		}
+		f := regField{index: i, name: sf.Name, offset: -1,
+			size: typeSize, order: binary.BigEndian}
+		for _, item := range strings.Split(tag, ",") {
+			kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("field %s: malformed tag item %q", sf.Name, item)
+			}
+			switch kv[0] {
+			case "offset", "size":
+				n, err := strconv.ParseUint(kv[1], 0, 8)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+				}
+				if kv[0] == "offset" {
+					f.offset = int(n)
+				} else {
+					f.size = int(n)
+				}
+			case "order":
+				switch kv[1] {
+				case "be":
+					f.order = binary.BigEndian
+				case "le":
+					f.order = binary.LittleEndian
+				default:
+					return nil, fmt.Errorf("field %s: unknown byte order %q", sf.Name, kv[1])
+				}
+			default:
+				return nil, fmt.Errorf("field %s: unknown tag key %q", sf.Name, kv[0])
+			}
+		}
+		if f.offset < 0 {
+			return nil, fmt.Errorf("field %s: offset not specified", sf.Name)
+		}
+		if f.size < 1 || f.size > typeSize {
+			return nil, fmt.Errorf("field %s: size %d out of range 1..%d",
+				sf.Name, f.size, typeSize)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// structValue return addressable struct value pointed by v.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("pointer to struct expected")
+	}
+	return rv.Elem(), nil
+}
+
+// ReadRegStruct reads register block from I2C-device starting from reg
+// address and decode it to struct pointed by v, according to field tags
+// (see structTag for tag format). Block length is calculated from
+// the most distant field end.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegStruct(reg byte, s interface{}) error {
+	rv, err := structValue(s)
+	if err != nil {
+		return err
+	}
+	fields, err := parseRegFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	var n int
+	for _, f := range fields {
+		if f.offset+f.size > n {
+			n = f.offset + f.size
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	buf, _, err := v.readRegBytes(reg, n)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		u := decodeUint(buf[f.offset:f.offset+f.size], f.order)
+		fv := rv.Field(f.index)
+		switch fv.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fv.SetInt(signExtend(u, uint(f.size)*8))
+		default:
+			fv.SetUint(u)
+		}
+	}
+	v.log.Debugf("Read struct %v (%d bytes) from reg 0x%0X", rv.Type(), n, reg)
+	return nil
+}
+
+// FormatRegStruct format struct pointed by s field by field, annotated
+// with offsets and sizes declared in field tags (see structTag).
+// Useful to see what was decoded by ReadRegStruct during
+// register map debugging. Output looks like:
+//
+//	Config @0x00 [1] = 24 (0x18)
+//	Temp   @0x01 [2] = -12 (0xFFF4)
+func FormatRegStruct(s interface{}) string {
+	rv, err := structValue(s)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	fields, err := parseRegFields(rv.Type())
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	var width int
+	for _, f := range fields {
+		if len(f.name) > width {
+			width = len(f.name)
+		}
+	}
+	var buf bytes.Buffer
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		var val string
+		var u uint64
+		switch fv.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			val = strconv.FormatInt(fv.Int(), 10)
+			u = uint64(fv.Int())
+		default:
+			val = strconv.FormatUint(fv.Uint(), 10)
+			u = fv.Uint()
+		}
+		if f.size < 8 {
+			u &= 1<<(uint(f.size)*8) - 1
+		}
+		fmt.Fprintf(&buf, "%-*s @0x%02X [%d] = %s (0x%0*X)\n",
+			width, f.name, f.offset, f.size, val, f.size*2, u)
+	}
+	return buf.String()
+}
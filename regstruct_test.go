@@ -0,0 +1,36 @@
+package i2c
+
+import "testing"
+
+type testRegStruct struct {
+	Config uint8  `i2c:"offset=0"`
+	Temp   int16  `i2c:"offset=1"`
+	Count  uint32 `i2c:"offset=3,size=3,order=le"`
+	note   string
+}
+
+func TestFormatRegStruct(t *testing.T) {
+	s := testRegStruct{Config: 0x18, Temp: -12, Count: 0x010203}
+	want := "Config @0x00 [1] = 24 (0x18)\n" +
+		"Temp   @0x01 [2] = -12 (0xFFF4)\n" +
+		"Count  @0x03 [3] = 66051 (0x010203)\n"
+	if got := FormatRegStruct(&s); got != want {
+		t.Errorf("FormatRegStruct:\n%s\nwant:\n%s", got, want)
+	}
+	if got := FormatRegStruct(s); got != "<pointer to struct expected>" {
+		t.Errorf("FormatRegStruct of non-pointer: %q", got)
+	}
+}
+
+func TestReadRegStruct(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	copy(a.chip(0x48).regs[0x20:], []byte{0x18, 0xFF, 0xF4, 0x03, 0x02, 0x01})
+	var s testRegStruct
+	if err := v.ReadRegStruct(0x20, &s); err != nil {
+		t.Fatal(err)
+	}
+	want := testRegStruct{Config: 0x18, Temp: -12, Count: 0x010203}
+	if s != want {
+		t.Errorf("ReadRegStruct = %+v, want %+v", s, want)
+	}
+}
@@ -0,0 +1,58 @@
+package i2c
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// defaultTransientErrors are errors, which typically signal transient
+// failure on busy or electrically noisy bus: arbitration lost, timeout
+// or NAK caused by glitch. Device removal (ENODEV) isn't among them.
+var defaultTransientErrors = []syscall.Errno{syscall.EIO, syscall.EREMOTEIO,
+	syscall.EAGAIN, syscall.ETIMEDOUT}
+
+// retryPolicy describe retries of failed transfers.
+type retryPolicy struct {
+	count  int
+	delay  time.Duration
+	errnos []syscall.Errno
+}
+
+// allow return true, if transfer failed with err
+// may be retried after attempt retries done.
+func (p *retryPolicy) allow(attempt int, err error) bool {
+	if attempt >= p.count {
+		return false
+	}
+	for _, errno := range p.errnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRetries make connection retry failed raw transfers (reads and
+// writes, including ones made by register helpers) up to count times,
+// pausing for delay before each retry. Only transfers failed with one
+// of errnos are retried: if none specified, EIO, EREMOTEIO, EAGAIN
+// and ETIMEDOUT are treated as transient. Zero count disable retries.
+// Note, that retried write of register address and data is safe,
+// but retried read continues from current register pointer.
+func (v *I2C) SetRetries(count int, delay time.Duration, errnos ...syscall.Errno) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(errnos) == 0 {
+		errnos = defaultTransientErrors
+	}
+	v.retry = retryPolicy{count: count, delay: delay,
+		errnos: append([]syscall.Errno(nil), errnos...)}
+}
+
+// SetKernelRetries set number of times I2C adapter driver retries
+// transfer on arbitration lost (I2C_RETRIES ioctl). Unlike SetRetries,
+// retries are done by kernel, and not all drivers respect the setting.
+func (v *I2C) SetKernelRetries(count int) error {
+	return devIoctl(v.rc, I2C_RETRIES, uintptr(count))
+}
@@ -0,0 +1,86 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetriesTransient(t *testing.T) {
+	v, a, clock := newFake(t, 0x48)
+	quietLog(t)
+	copy(a.chip(0x48).regs[:], []byte{0x11, 0x22})
+	v.SetRetries(3, 5*time.Millisecond)
+
+	// Fail N times, then succeed.
+	a.failNext(syscall.EIO, syscall.EREMOTEIO, syscall.EIO)
+	buf := make([]byte, 2)
+	n, err := v.ReadBytes(buf)
+	if err != nil || n != 2 || !bytes.Equal(buf, []byte{0x11, 0x22}) {
+		t.Fatalf("ReadBytes = [% X], %d, %v", buf, n, err)
+	}
+	if slept := clock.Slept(); len(slept) != 3 {
+		t.Errorf("delays %v, want 3 x 5ms", slept)
+	}
+
+	// More failures, than retries allowed.
+	a.failNext(syscall.EIO, syscall.EIO, syscall.EIO, syscall.EIO)
+	if _, err := v.ReadBytes(buf); !errors.Is(err, syscall.EIO) {
+		t.Errorf("error %v, want EIO", err)
+	}
+	a.reset()
+
+	// Writes are retried too.
+	a.failNext(syscall.EAGAIN)
+	if _, err := v.WriteBytes([]byte{0x05, 0xAB}); err != nil {
+		t.Fatal(err)
+	}
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x05, 0xAB}) {
+		t.Errorf("writes %X, want [[05 AB]]", w)
+	}
+}
+
+func TestRetriesNotTransient(t *testing.T) {
+	v, a, clock := newFake(t, 0x48)
+	quietLog(t)
+	v.SetRetries(3, 5*time.Millisecond)
+	buf := make([]byte, 1)
+	a.failNext(syscall.ENODEV)
+	if _, err := v.ReadBytes(buf); !errors.Is(err, syscall.ENODEV) {
+		t.Fatalf("error %v, want ENODEV", err)
+	}
+	if slept := clock.Slept(); len(slept) != 0 {
+		t.Errorf("ENODEV retried with delays %v", slept)
+	}
+
+	// Custom errno set.
+	v.SetRetries(1, time.Millisecond, syscall.ENODEV)
+	a.failNext(syscall.ENODEV)
+	if _, err := v.ReadBytes(buf); err != nil {
+		t.Fatalf("ENODEV not retried: %v", err)
+	}
+	a.failNext(syscall.EIO)
+	if _, err := v.ReadBytes(buf); !errors.Is(err, syscall.EIO) {
+		t.Errorf("error %v, want EIO", err)
+	}
+
+	// Zero count disable retries.
+	v.SetRetries(0, time.Millisecond)
+	a.failNext(syscall.EIO)
+	if _, err := v.ReadBytes(buf); !errors.Is(err, syscall.EIO) {
+		t.Errorf("error %v, want EIO", err)
+	}
+}
+
+func TestSetKernelRetries(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	if err := v.SetKernelRetries(4); err != nil {
+		t.Fatal(err)
+	}
+	ops := a.operations("ioctl")
+	if len(ops) != 1 || ops[0].cmd != I2C_RETRIES || ops[0].arg != 4 {
+		t.Errorf("ioctls %+v, want I2C_RETRIES 4", ops)
+	}
+}
@@ -0,0 +1,109 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// RingReader repeatedly reads samples from I2C-device register and
+// keeps them in fixed size ring buffer, overwriting the oldest ones.
+// Suitable for sliding window computations over sensor data.
+// RingReader is safe for concurrent use.
+type RingReader struct {
+	i2c    *I2C
+	reg    byte
+	width  int
+	order  binary.ByteOrder
+	signed bool
+
+	mu    sync.Mutex
+	ring  []int32
+	next  int
+	count int
+}
+
+// NewRingReader create RingReader, which reads samples of width bytes
+// (1..4) from register reg, decoding them with byte order specified
+// in order, as signed (sign-extended) or unsigned values,
+// and keeps the latest size samples.
+func (v *I2C) NewRingReader(reg byte, width int, order binary.ByteOrder,
+	signed bool, size int) (*RingReader, error) {
+
+	if width < 1 || width > 4 {
+		return nil, fmt.Errorf("sample width %d out of range 1..4", width)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("ring size %d must be positive", size)
+	}
+	r := &RingReader{i2c: v, reg: reg, width: width, order: order,
+		signed: signed, ring: make([]int32, size)}
+	return r, nil
+}
+
+// Sample reads one sample from I2C-device and push it to the ring.
+func (r *RingReader) Sample() error {
+	r.i2c.mu.Lock()
+	u, err := r.i2c.readRegUint(r.reg, r.width, r.order)
+	r.i2c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	var s int32
+	if r.signed {
+		s = int32(signExtend(u, uint(r.width)*8))
+	} else {
+		s = int32(u)
+	}
+	r.push(s)
+	return nil
+}
+
+// Fill reads count samples in a row and push them to the ring.
+func (r *RingReader) Fill(count int) error {
+	for i := 0; i < count; i++ {
+		if err := r.Sample(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RingReader) push(s int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ring[r.next] = s
+	r.next = (r.next + 1) % len(r.ring)
+	if r.count < len(r.ring) {
+		r.count++
+	}
+}
+
+// Len return number of samples kept in the ring.
+func (r *RingReader) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Latest return copy of the latest n samples, from oldest to newest.
+// If ring keeps less than n samples, all of them are returned.
+func (r *RingReader) Latest(n int) []int32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > r.count {
+		n = r.count
+	}
+	if n < 0 {
+		n = 0
+	}
+	out := make([]int32, n)
+	start := r.next - n
+	if start < 0 {
+		start += len(r.ring)
+	}
+	for i := range out {
+		out[i] = r.ring[(start+i)%len(r.ring)]
+	}
+	return out
+}
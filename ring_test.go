@@ -0,0 +1,50 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestRingReader(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	// Sample register counts reads: 1, 2, 3...
+	a.chip(0x40).onRead = func(c *fakeChip) { c.regs[0x20]++ }
+	r, err := v.NewRingReader(0x20, 1, binary.BigEndian, false, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Fill(3); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Latest(10); !reflect.DeepEqual(got, []int32{1, 2, 3}) {
+		t.Errorf("Latest before wraparound = %v", got)
+	}
+	if err := r.Fill(3); err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 4 {
+		t.Errorf("Len = %d, want 4", r.Len())
+	}
+	if got := r.Latest(4); !reflect.DeepEqual(got, []int32{3, 4, 5, 6}) {
+		t.Errorf("Latest(4) after wraparound = %v", got)
+	}
+	if got := r.Latest(2); !reflect.DeepEqual(got, []int32{5, 6}) {
+		t.Errorf("Latest(2) after wraparound = %v", got)
+	}
+}
+
+func TestRingReaderSigned(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	copy(a.chip(0x40).regs[0x20:], []byte{0xFF, 0xFE})
+	r, err := v.NewRingReader(0x20, 2, binary.BigEndian, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Sample(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Latest(1); !reflect.DeepEqual(got, []int32{-2}) {
+		t.Errorf("signed sample = %v, want [-2]", got)
+	}
+}
@@ -0,0 +1,228 @@
+package i2c
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Range of valid 7-bit device addresses, excluding reserved ones.
+const (
+	firstAddr = 0x03
+	lastAddr  = 0x77
+)
+
+// ListBuses return sorted numbers of I2C buses available in the system,
+// found as /dev/i2c-N device files (i2c-dev kernel module must be loaded).
+// Lets code find buses on boards with different numbering.
+func ListBuses() ([]int, error) {
+	paths, err := filepath.Glob(busPrefix + "*")
+	if err != nil {
+		return nil, err
+	}
+	var buses []int
+	for _, path := range paths {
+		bus, err := strconv.Atoi(strings.TrimPrefix(path, busPrefix))
+		if err != nil {
+			continue
+		}
+		buses = append(buses, bus)
+	}
+	sort.Ints(buses)
+	return buses, nil
+}
+
+// BusExists return true, if I2C bus device file /dev/i2c-N
+// for bus exists.
+func BusExists(bus int) bool {
+	_, err := os.Stat(busPath(bus))
+	return err == nil
+}
+
+// OpenAllByID scan all I2C buses for devices at candidate addresses addrs
+// (usually the few addresses device can be strapped to, see datasheet),
+// read identification register idReg at each responding one, and return
+// open connections to every device, which identification match expectedID.
+// Supports setups with several identical sensors. Only addresses listed
+// in addrs are accessed, since ID read starts with register address
+// write, which might disturb unrelated devices. Each bus is probed via
+// single file descriptor, while every match gets connection of its own.
+// If addrs is nil, whole range of valid 7-bit addresses (0x03..0x77)
+// is scanned: use with care, since every responding device get
+// its register pointer written.
+func OpenAllByID(idReg byte, expectedID []byte, addrs []uint8) ([]*I2C, error) {
+	if addrs == nil {
+		addrs = allAddrs()
+	}
+	return findByID(idReg, expectedID, addrs, false)
+}
+
+// allAddrs return range of valid 7-bit device addresses.
+func allAddrs() []uint8 {
+	addrs := make([]uint8, 0, lastAddr-firstAddr+1)
+	for addr := firstAddr; addr <= lastAddr; addr++ {
+		addrs = append(addrs, uint8(addr))
+	}
+	return addrs
+}
+
+// findByID scan all I2C buses for devices at addresses addrs, which
+// identification register idReg match expectedID, and open connections
+// to them, stopping at the first one, if first is true.
+func findByID(idReg byte, expectedID []byte, addrs []uint8, first bool) ([]*I2C, error) {
+	buses, err := ListBuses()
+	if err != nil {
+		return nil, err
+	}
+	var found []*I2C
+	for _, bus := range buses {
+		matches, err := matchByID(bus, idReg, expectedID, addrs)
+		if err != nil {
+			lg.Debugf("Skip bus %d: %v", bus, err)
+			continue
+		}
+		for _, addr := range matches {
+			v, err := NewI2C(addr, bus)
+			if err != nil {
+				lg.Debugf("Can't open bus %d, address 0x%0X: %v", bus, addr, err)
+				continue
+			}
+			lg.Debugf("Found device with ID [% X] on bus %d, address 0x%0X",
+				expectedID, bus, addr)
+			found = append(found, v)
+			if first {
+				return found, nil
+			}
+		}
+	}
+	return found, nil
+}
+
+// matchByID probe addresses addrs on bus via single file descriptor,
+// and return those, where identification register idReg match expectedID.
+// Addresses claimed by kernel driver and not responding are skipped.
+func matchByID(bus int, idReg byte, expectedID []byte, addrs []uint8) ([]uint8, error) {
+	f, err := openDevice(busPath(bus))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var matches []uint8
+	id := make([]byte, len(expectedID))
+	for _, addr := range addrs {
+		if err := devIoctl(f, I2C_SLAVE, uintptr(addr)); err != nil {
+			lg.Debugf("Skip address 0x%0X on bus %d: %v", addr, bus, err)
+			continue
+		}
+		if _, err := f.Write([]byte{idReg}); err != nil {
+			continue
+		}
+		if _, err := f.Read(id); err != nil || !bytes.Equal(id, expectedID) {
+			continue
+		}
+		matches = append(matches, addr)
+	}
+	return matches, nil
+}
+
+// AutoBus scan all I2C buses for device at address addr, which
+// identification register idReg match expectedID, and returns open
+// connection to the first match. Removes hard-coded bus numbers from
+// code, running on different boards. Only address addr is accessed,
+// via single file descriptor per bus. Returns ErrDeviceNotFound,
+// if no device match.
+func AutoBus(addr uint8, idReg byte, expectedID []byte) (*I2C, error) {
+	found, err := findByID(idReg, expectedID, []uint8{addr}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ErrDeviceNotFound
+	}
+	return found[0], nil
+}
+
+// WaitForDevice wait until I2C-device appears at address addr on bus,
+// probing it (one byte read) every poll interval, and returns open
+// connection as soon as device acknowledge. Returns ctx error, if ctx
+// is done first. Supports setups, where sensor is attached after
+// program start. Options opts are passed to NewI2C
+// (WithClock affects poll timing as well).
+func WaitForDevice(ctx context.Context, addr uint8, bus int, poll time.Duration,
+	opts ...Option) (*I2C, error) {
+
+	// Extract clock from options.
+	cfg := &I2C{clock: systemClock{}}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		v, err := NewI2C(addr, bus, opts...)
+		if err == nil {
+			buf := make([]byte, 1)
+			if _, err = v.read(buf); err == nil {
+				lg.Debugf("Device appeared at address 0x%0X on bus %d", addr, bus)
+				return v, nil
+			}
+			v.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-cfg.clock.After(poll):
+		}
+	}
+}
+
+// ScanBus probe every address in range 0x03..0x77 on bus (one byte
+// read, like "i2cdetect -r" does) and return addresses, where device
+// acknowledge. Addresses claimed by kernel driver (EBUSY) aren't
+// probed, but reported as present too, since some device is bound
+// there ("UU" in i2cdetect output). Bus is opened with its own file
+// descriptor, which is closed at the end, so no connection is left
+// bound to the last probed address. Note, that read might disturb
+// some devices (write-only ones, for instance), so use it with care.
+func ScanBus(bus int) ([]uint8, error) {
+	return ScanBusContext(context.Background(), bus)
+}
+
+// ScanBusContext is a variant of ScanBus, which stops with ctx.Err(),
+// when ctx is canceled or its deadline passes.
+func ScanBusContext(ctx context.Context, bus int) ([]uint8, error) {
+	f, err := openDevice(busPath(bus))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var found []uint8
+	buf := make([]byte, 1)
+	for addr := firstAddr; addr <= lastAddr; addr++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := devIoctl(f, I2C_SLAVE, uintptr(addr))
+		if errors.Is(err, syscall.EBUSY) {
+			lg.Debugf("Address 0x%0X on bus %d claimed by kernel driver", addr, bus)
+			found = append(found, uint8(addr))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Read(buf); err != nil {
+			continue
+		}
+		lg.Debugf("Device found at address 0x%0X on bus %d", addr, bus)
+		found = append(found, uint8(addr))
+	}
+	return found, nil
+}
@@ -0,0 +1,151 @@
+package i2c
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newIDAdapter create fake adapter with chips at addrs,
+// each having identification register 0xD0 set to id.
+func newIDAdapter(id byte, addrs ...uint16) *fakeAdapter {
+	a := newFakeAdapter()
+	for _, addr := range addrs {
+		a.chip(addr).regs[0xD0] = id
+	}
+	return a
+}
+
+func TestOpenAllByID(t *testing.T) {
+	bus0 := newIDAdapter(0x58, 0x76)
+	bus0.chip(0x77).regs[0xD0] = 0x60
+	bus1 := newIDAdapter(0x58, 0x76, 0x20)
+	installFakeBuses(t, map[int]*fakeAdapter{0: bus0, 1: bus1})
+
+	found, err := OpenAllByID(0xD0, []byte{0x58}, []uint8{0x76, 0x77})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("%d devices found, want 2", len(found))
+	}
+	for i, v := range found {
+		if v.GetBus() != i || v.GetAddr() != 0x76 {
+			t.Errorf("device %d found at bus %d, address 0x%02X", i, v.GetBus(), v.GetAddr())
+		}
+		v.Close()
+	}
+	// One descriptor to probe bus, one for connection to the match.
+	for i, a := range []*fakeAdapter{bus0, bus1} {
+		if a.opens != 2 {
+			t.Errorf("bus %d opened %d times, want 2", i, a.opens)
+		}
+	}
+	// Addresses not listed aren't touched.
+	for _, op := range bus1.operations("read", "write") {
+		if op.addr == 0x20 {
+			t.Errorf("unlisted address accessed: %+v", op)
+		}
+	}
+}
+
+func TestOpenAllByIDAllAddrs(t *testing.T) {
+	bus0 := newIDAdapter(0x58, 0x03, 0x20, 0x77)
+	bus0.chip(0x21).regs[0xD0] = 0x60
+	installFakeBuses(t, map[int]*fakeAdapter{0: bus0})
+
+	found, err := OpenAllByID(0xD0, []byte{0x58}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []uint8
+	for _, v := range found {
+		addrs = append(addrs, v.GetAddr())
+		v.Close()
+	}
+	if want := []uint8{0x03, 0x20, 0x77}; !reflect.DeepEqual(addrs, want) {
+		t.Errorf("devices found at %#v, want %#v", addrs, want)
+	}
+}
+
+func TestAutoBus(t *testing.T) {
+	bus0 := newIDAdapter(0x60, 0x76)
+	bus1 := newIDAdapter(0x58, 0x76)
+	bus2 := newIDAdapter(0x58, 0x76)
+	installFakeBuses(t, map[int]*fakeAdapter{0: bus0, 1: bus1, 2: bus2})
+
+	v, err := AutoBus(0x76, 0xD0, []byte{0x58})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if v.GetBus() != 1 {
+		t.Errorf("device found on bus %d, want the first match on bus 1", v.GetBus())
+	}
+	if bus2.opens != 0 {
+		t.Error("scan not stopped at the first match")
+	}
+
+	if _, err := AutoBus(0x76, 0xD0, []byte{0x77}); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("expected ErrDeviceNotFound, got %v", err)
+	}
+}
+
+func TestWaitForDevice(t *testing.T) {
+	a := newFakeAdapter()
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+	clock := newFakeClock()
+	// Device attached, but doesn't respond for first 3 polls.
+	a.chip(0x40).nak = 3
+	v, err := WaitForDevice(context.Background(), 0x40, 1, 100*time.Millisecond, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if v.GetAddr() != 0x40 {
+		t.Errorf("handle bound to address 0x%02X", v.GetAddr())
+	}
+	slept := clock.Slept()
+	if len(slept) != 3 || slept[0] != 100*time.Millisecond {
+		t.Errorf("delays between polls %v, want 3 of 100ms", slept)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WaitForDevice(ctx, 0x41, 1, time.Millisecond, WithClock(clock)); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestListBuses(t *testing.T) {
+	installFakeBuses(t, map[int]*fakeAdapter{
+		10: newFakeAdapter(), 2: newFakeAdapter(), 0: newFakeAdapter(),
+	})
+	// Files not named after bus number are skipped.
+	for _, name := range []string{"i2c-x", "i2c-", "spidev0"} {
+		path := filepath.Join(filepath.Dir(busPrefix), name)
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buses, err := ListBuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Numeric, not lexical, order.
+	if !reflect.DeepEqual(buses, []int{0, 2, 10}) {
+		t.Errorf("ListBuses = %v, want [0 2 10]", buses)
+	}
+	if !BusExists(2) || BusExists(1) {
+		t.Errorf("BusExists(2) = %v, BusExists(1) = %v", BusExists(2), BusExists(1))
+	}
+
+	installFakeBuses(t, nil)
+	if buses, err := ListBuses(); err != nil || len(buses) != 0 {
+		t.Errorf("ListBuses without buses = %v, %v", buses, err)
+	}
+}
@@ -0,0 +1,87 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Schema describe set of registers to read by ReadSchema.
+// It is loaded from JSON in format:
+//
+//	{
+//	  "registers": [
+//	    {"name": "CHIP_ID", "reg": 208, "width": 1},
+//	    {"name": "TEMP", "reg": 250, "width": 2, "signed": true, "order": "le"}
+//	  ]
+//	}
+//
+// where width is a value length in bytes (1..8, default 1),
+// order is a byte order ("be" by default, or "le")
+// and signed request sign extension of value.
+type Schema struct {
+	Registers []SchemaReg `json:"registers"`
+}
+
+// SchemaReg describe single register of Schema.
+type SchemaReg struct {
+	Name   string `json:"name"`
+	Reg    byte   `json:"reg"`
+	Width  int    `json:"width"`
+	Signed bool   `json:"signed"`
+	Order  string `json:"order"`
+}
+
+// NamedValue is a register value decoded according to Schema.
+type NamedValue struct {
+	Name  string
+	Reg   byte
+	Value int64
+}
+
+// byteOrder return byte order of register described.
+func (s *SchemaReg) byteOrder() (binary.ByteOrder, error) {
+	switch s.Order {
+	case "", "be":
+		return binary.BigEndian, nil
+	case "le":
+		return binary.LittleEndian, nil
+	}
+	return nil, fmt.Errorf("register %s: unknown byte order %q", s.Name, s.Order)
+}
+
+// ReadSchema parse register schema from r (see Schema for format)
+// and reads all registers described from I2C-device under one
+// connection lock, returning decoded values in schema order.
+// Turns package to data-driven device inspector.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadSchema(r io.Reader) ([]NamedValue, error) {
+	var schema Schema
+	if err := json.NewDecoder(r).Decode(&schema); err != nil {
+		return nil, fmt.Errorf("can't parse register schema: %w", err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	values := make([]NamedValue, 0, len(schema.Registers))
+	for _, item := range schema.Registers {
+		order, err := item.byteOrder()
+		if err != nil {
+			return nil, err
+		}
+		width := item.Width
+		if width == 0 {
+			width = 1
+		}
+		u, err := v.readRegUint(item.Reg, width, order)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", item.Name, err)
+		}
+		value := int64(u)
+		if item.Signed {
+			value = signExtend(u, uint(width)*8)
+		}
+		values = append(values, NamedValue{Name: item.Name, Reg: item.Reg, Value: value})
+	}
+	return values, nil
+}
@@ -0,0 +1,46 @@
+package i2c
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadSchema(t *testing.T) {
+	v, a, _ := newFake(t, 0x76)
+	c := a.chip(0x76)
+	c.regs[0xD0] = 0x60
+	copy(c.regs[0xFA:], []byte{0x38, 0xFF})
+	copy(c.regs[0x88:], []byte{0x70, 0x6B, 0x00, 0x01})
+	schema := `{
+	  "registers": [
+	    {"name": "CHIP_ID", "reg": 208},
+	    {"name": "TEMP", "reg": 250, "width": 2, "signed": true, "order": "le"},
+	    {"name": "DIG_T1", "reg": 136, "width": 2, "order": "le"},
+	    {"name": "RAW", "reg": 136, "width": 4}
+	  ]
+	}`
+	values, err := v.ReadSchema(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NamedValue{
+		{Name: "CHIP_ID", Reg: 0xD0, Value: 0x60},
+		{Name: "TEMP", Reg: 0xFA, Value: -200},
+		{Name: "DIG_T1", Reg: 0x88, Value: 0x6B70},
+		{Name: "RAW", Reg: 0x88, Value: 0x706B0001},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %+v, want %+v", values, want)
+	}
+
+	bad := []string{
+		`{"registers": [`,
+		`{"registers": [{"name": "X", "reg": 1, "order": "middle"}]}`,
+	}
+	for _, s := range bad {
+		if _, err := v.ReadSchema(strings.NewReader(s)); err == nil {
+			t.Errorf("schema %s accepted", s)
+		}
+	}
+}
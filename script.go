@@ -0,0 +1,92 @@
+package i2c
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ScriptStep is a single step of device initialization
+// script, executed by RunScript.
+type ScriptStep interface {
+	run(ctx context.Context, v *I2C) error
+	String() string
+}
+
+// WriteStep writes Value to register Reg.
+type WriteStep struct {
+	Reg   byte
+	Value byte
+}
+
+func (s WriteStep) run(ctx context.Context, v *I2C) error {
+	return v.writeRegU8(s.Reg, s.Value)
+}
+
+func (s WriteStep) String() string {
+	return fmt.Sprintf("write 0x%02X to reg 0x%02X", s.Value, s.Reg)
+}
+
+// DelayStep pause script for D.
+type DelayStep struct {
+	D time.Duration
+}
+
+func (s DelayStep) run(ctx context.Context, v *I2C) error {
+	return sleepContext(ctx, v.clock, s.D)
+}
+
+func (s DelayStep) String() string {
+	return fmt.Sprintf("delay %v", s.D)
+}
+
+// VerifyStep reads register Reg and checks, that bits selected
+// by Mask are equal to Expected, aborting script otherwise.
+type VerifyStep struct {
+	Reg      byte
+	Mask     byte
+	Expected byte
+}
+
+func (s VerifyStep) run(ctx context.Context, v *I2C) error {
+	b, err := v.readRegU8(s.Reg)
+	if err != nil {
+		return err
+	}
+	if b&s.Mask != s.Expected&s.Mask {
+		return fmt.Errorf("read 0x%02X, masked value 0x%02X doesn't match expected 0x%02X",
+			b, b&s.Mask, s.Expected&s.Mask)
+	}
+	return nil
+}
+
+func (s VerifyStep) String() string {
+	return fmt.Sprintf("verify reg 0x%02X & 0x%02X == 0x%02X", s.Reg, s.Mask, s.Expected)
+}
+
+// RunScript executes initialization script steps in order under
+// connection lock, aborting at first failure. Returned error
+// pinpoints failed step by its number (counting from 1) and
+// description, so device bring-up fails loudly at exact place.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) RunScript(steps []ScriptStep) error {
+	return v.RunScriptContext(context.Background(), steps)
+}
+
+// RunScriptContext is a variant of RunScript, where whole script
+// is bounded by ctx: script is aborted with ctx.Err(), when ctx
+// is canceled or its deadline passes.
+func (v *I2C) RunScriptContext(ctx context.Context, steps []ScriptStep) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, step := range steps {
+		v.log.Debugf("Script step %d: %v", i+1, step)
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("script step %d (%v) canceled: %w", i+1, step, err)
+		}
+		if err := step.run(ctx, v); err != nil {
+			return fmt.Errorf("script step %d (%v) failed: %w", i+1, step, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package i2c
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunScriptVerifyFails(t *testing.T) {
+	v, a, clock := newFake(t, 0x40)
+	c := a.chip(0x40)
+	// Device never reports ready bit 0x80 in status reg 0x01.
+	c.onRead = func(c *fakeChip) { c.regs[0x01] = 0x03 }
+	script := []ScriptStep{
+		WriteStep{Reg: 0x00, Value: 0x80},
+		DelayStep{D: 10 * time.Millisecond},
+		VerifyStep{Reg: 0x01, Mask: 0x03, Expected: 0x03},
+		VerifyStep{Reg: 0x01, Mask: 0x80, Expected: 0x80},
+		WriteStep{Reg: 0x02, Value: 0x01},
+	}
+	err := v.RunScript(script)
+	if err == nil {
+		t.Fatal("failing verify step passed")
+	}
+	for _, s := range []string{"step 4", "verify reg 0x01 & 0x80 == 0x80", "read 0x03"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error %q doesn't contain %q", err, s)
+		}
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 10*time.Millisecond {
+		t.Errorf("delays %v, want [10ms]", slept)
+	}
+	// Steps after failure aren't executed.
+	if c.regs[0x02] != 0 {
+		t.Error("script continued after failed step")
+	}
+}
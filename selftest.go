@@ -0,0 +1,150 @@
+package i2c
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SelfTest verify I2C path end to end, running quick sequence of checks:
+// file descriptor is open, adapter respond to I2C_FUNCS request,
+// and device at configured address acknowledge a probe (see Probe:
+// SMBus quick command, or one byte read if adapter doesn't support it).
+// Returns error, describing failed stage, or error wrapping ErrTimeout,
+// if all checks not finished within timeout. Designed for startup
+// diagnostics.
+func (v *I2C) SelfTest(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return v.SelfTestContext(ctx)
+}
+
+// SelfTestContext is a variant of SelfTest, which run checks until
+// ctx is canceled or its deadline passes. On deadline error wrapping
+// ErrTimeout is returned, on cancellation - ctx.Err().
+//
+// Stages run under connection lock in background goroutine, like
+// ReadBytesContext transfers: stage, which is running on return,
+// keeps connection locked until driver completes or aborts transfer
+// (see SetTimeout), though no further stages are started.
+func (v *I2C) SelfTestContext(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return v.selfTestCtxError(ctx, "open")
+	}
+	v.log.Debugf("Run self-test on bus %d, address 0x%0X...", v.bus, v.GetAddr16())
+	stage := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		done <- v.selfTest(ctx, stage)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		v.log.Debugf("Self-test passed")
+		return nil
+	case <-ctx.Done():
+		name := "open"
+		select {
+		case name = <-stage:
+		default:
+		}
+		return v.selfTestCtxError(ctx, name)
+	}
+}
+
+// selfTestCtxError return error for self-test interrupted by ctx
+// at stage name.
+func (v *I2C) selfTestCtxError(ctx context.Context, name string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: self-test not finished, interrupted at %s stage",
+			ErrTimeout, name)
+	}
+	return ctx.Err()
+}
+
+// selfTest run self-test stages in order, until one fail or ctx
+// is done, reporting name of stage being started to stage channel
+// (only latest one is kept). Connection lock must be held.
+func (v *I2C) selfTest(ctx context.Context, stage chan string) error {
+	stages := []struct {
+		name  string
+		check func() error
+	}{
+		{"open", v.checkOpen},
+		{"functionality", func() error {
+			funcs, err := v.Functions()
+			if err == nil {
+				v.log.Debugf("Adapter functionality: 0x%08X", funcs)
+			}
+			return err
+		}},
+		{"probe", func() error {
+			ok, err := v.probe()
+			if err == nil && !ok {
+				err = fmt.Errorf("device at address 0x%0X not acknowledged", v.GetAddr16())
+			}
+			return err
+		}},
+	}
+	for _, st := range stages {
+		if err := ctx.Err(); err != nil {
+			return v.selfTestCtxError(ctx, st.name)
+		}
+		select {
+		case <-stage:
+		default:
+		}
+		stage <- st.name
+		if err := st.check(); err != nil {
+			return fmt.Errorf("self-test failed at %s stage: %w", st.name, err)
+		}
+	}
+	return nil
+}
+
+// checkOpen verify, that device file is open.
+func (v *I2C) checkOpen() error {
+	if v.rc == nil {
+		return errors.New("connection closed")
+	}
+	if f, ok := v.rc.(*os.File); ok {
+		if _, err := f.Stat(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MeasureThroughput estimate bus throughput, timing iterations
+// of block reads of n bytes starting from reg address.
+// Returns approximate number of data bytes transferred per second
+// (register address writes are not counted). Helps to pick sensible
+// chunk sizes and timeouts empirically.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) MeasureThroughput(reg byte, n int, iterations int) (bytesPerSec float64, err error) {
+	if n < 1 || iterations < 1 {
+		return 0, fmt.Errorf("invalid measurement parameters: n=%d, iterations=%d",
+			n, iterations)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	start := v.clock.Now()
+	for i := 0; i < iterations; i++ {
+		if _, _, err := v.readRegBytes(reg, n); err != nil {
+			return 0, err
+		}
+	}
+	elapsed := v.clock.Now().Sub(start)
+	if elapsed <= 0 {
+		return 0, errors.New("elapsed time too small to measure throughput")
+	}
+	bytesPerSec = float64(n*iterations) / elapsed.Seconds()
+	v.log.Debugf("Measured throughput %.1f bytes/sec", bytesPerSec)
+	return bytesPerSec, nil
+}
@@ -0,0 +1,71 @@
+package i2c
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	if err := v.SelfTest(time.Second); err != nil {
+		t.Fatalf("SelfTest: %v", err)
+	}
+	// Probe is quick command, no data read.
+	if len(a.operations("read")) != 0 || len(a.operations("smbus")) != 1 {
+		t.Errorf("expected single quick command probe, got %+v", a.operations())
+	}
+}
+
+func TestSelfTestProbeFail(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x40).nak = 1
+	err := v.SelfTest(time.Second)
+	if err == nil || !strings.Contains(err.Error(), "probe stage") {
+		t.Fatalf("expected probe stage failure, got %v", err)
+	}
+}
+
+func TestSelfTestTimeout(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	// Adapter hangs: functionality request doesn't complete until unlocked.
+	a.mu.Lock()
+	err := v.SelfTest(20 * time.Millisecond)
+	a.mu.Unlock()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "functionality stage") {
+		t.Errorf("expected interrupted stage in error, got %v", err)
+	}
+}
+
+func TestSelfTestContextCanceled(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := v.SelfTestContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if ops := a.operations(); len(ops) != 0 {
+		t.Errorf("operations done after cancellation: %+v", ops)
+	}
+}
+
+func TestMeasureThroughput(t *testing.T) {
+	v, a, clock := newFake(t, 0x40)
+	// Each block read takes 1 ms.
+	a.chip(0x40).onRead = func(*fakeChip) { clock.Advance(time.Millisecond) }
+	rate, err := v.MeasureThroughput(0x00, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 10000 {
+		t.Errorf("throughput %v bytes/sec, want 10000", rate)
+	}
+	if _, err := v.MeasureThroughput(0x00, 0, 5); err == nil {
+		t.Error("zero block size accepted")
+	}
+}
@@ -0,0 +1,150 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// smbusAccess perform SMBus transaction of type size (one of
+// I2C_SMBUS_... constants) with command byte in direction readWrite
+// (I2C_SMBUS_READ or I2C_SMBUS_WRITE) via I2C_SMBUS ioctl. Kernel
+// takes care of protocol details, like block count byte and PEC.
+func (v *I2C) smbusAccess(readWrite uint8, command uint8, size uint32,
+	data *i2cSmbusData) error {
+
+	args := i2cSmbusIoctlData{readWrite: readWrite, command: command,
+		size: size, data: data}
+	if err := devIoctlPtr(v.rc, I2C_SMBUS, unsafe.Pointer(&args)); err != nil {
+		if readWrite == I2C_SMBUS_READ {
+			v.setReadError(err)
+		} else {
+			v.setWriteError(err)
+		}
+		return v.opError("smbus transfer", err)
+	}
+	return nil
+}
+
+// ReadRegBlock reads SMBus block from I2C-device register specified
+// in reg: device sends count byte first, followed by that many data
+// bytes (up to 32). Done with I2C_SMBUS ioctl, so adapter must support
+// I2C_FUNC_SMBUS_READ_BLOCK_DATA functionality.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegBlock(reg byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	var data i2cSmbusData
+	if err := v.smbusAccess(I2C_SMBUS_READ, reg, I2C_SMBUS_BLOCK_DATA, &data); err != nil {
+		return nil, err
+	}
+	count := int(data[0])
+	if count > i2cSmbusBlockMax {
+		return nil, fmt.Errorf("block length %d received from reg 0x%0X exceed %d bytes",
+			count, reg, i2cSmbusBlockMax)
+	}
+	buf := append([]byte(nil), data[1:count+1]...)
+	v.log.Debugf("[tx %d] Read block of %d bytes from reg 0x%0X: [% X]", tx, count, reg, buf)
+	return buf, nil
+}
+
+// WriteRegBlock writes SMBus block to I2C-device register specified
+// in reg: count byte followed by data (up to 32 bytes). Done with
+// I2C_SMBUS ioctl, so adapter must support
+// I2C_FUNC_SMBUS_WRITE_BLOCK_DATA functionality.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) WriteRegBlock(reg byte, data []byte) error {
+	if len(data) > i2cSmbusBlockMax {
+		return fmt.Errorf("block length %d exceed %d bytes", len(data), i2cSmbusBlockMax)
+	}
+	var block i2cSmbusData
+	block[0] = byte(len(data))
+	copy(block[1:], data)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	tx := nextTx()
+	if err := v.smbusAccess(I2C_SMBUS_WRITE, reg, I2C_SMBUS_BLOCK_DATA, &block); err != nil {
+		return err
+	}
+	v.log.Debugf("[tx %d] Write block of %d bytes to reg 0x%0X: [% X]", tx, len(data), reg, data)
+	return nil
+}
+
+// Probe check, whether I2C-device responds at its address. SMBus quick
+// command (address only, no data) is used, so register pointer state
+// of device isn't disturbed; if adapter doesn't support it, one byte
+// read is done instead. Returns false with nil error, if device hasn't
+// acknowledged (absent), and false with error on other bus failures.
+func (v *I2C) Probe() (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.probe()
+}
+
+// probe is Probe, which doesn't acquire connection lock.
+func (v *I2C) probe() (bool, error) {
+	err := v.smbusAccess(I2C_SMBUS_WRITE, 0, I2C_SMBUS_QUICK, nil)
+	if errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.EINVAL) {
+		v.log.Debugf("Quick command not supported: %v, probe with read", err)
+		_, err = v.read(make([]byte, 1))
+	}
+	if err == nil {
+		return true, nil
+	}
+	if isNak(err) || errors.Is(err, syscall.ENODEV) {
+		v.log.Debugf("Device at address 0x%0X not present: %v", v.GetAddr16(), err)
+		return false, nil
+	}
+	return false, err
+}
+
+// QuickWrite send SMBus quick command: device address with R/W bit
+// set to bit, without any data. Some simple devices use R/W bit itself
+// as a command (turn on/off, for instance). Adapter must support
+// I2C_FUNC_SMBUS_QUICK functionality.
+func (v *I2C) QuickWrite(bit bool) error {
+	var rw uint8 = I2C_SMBUS_WRITE
+	if bit {
+		rw = I2C_SMBUS_READ
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.smbusAccess(rw, 0, I2C_SMBUS_QUICK, nil); err != nil {
+		return err
+	}
+	v.log.Debugf("Quick command with bit %v sent", bit)
+	return nil
+}
+
+// SendByte send single byte b to I2C-device with SMBus send byte
+// command. Unlike WriteRegU8, no register address precede data:
+// b itself is command or data for devices without register map
+// (some DACs, switches). Adapter must support
+// I2C_FUNC_SMBUS_WRITE_BYTE functionality.
+func (v *I2C) SendByte(b byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.smbusAccess(I2C_SMBUS_WRITE, b, I2C_SMBUS_BYTE, nil); err != nil {
+		return err
+	}
+	v.log.Debugf("Sent byte 0x%02X", b)
+	return nil
+}
+
+// ReceiveByte receive single byte from I2C-device with SMBus receive
+// byte command. Unlike ReadRegU8, no register address is written
+// before read: byte comes from device current position (last
+// selected register, or the only data byte of simple devices).
+// Adapter must support I2C_FUNC_SMBUS_READ_BYTE functionality.
+func (v *I2C) ReceiveByte() (byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var data i2cSmbusData
+	if err := v.smbusAccess(I2C_SMBUS_READ, 0, I2C_SMBUS_BYTE, &data); err != nil {
+		return 0, err
+	}
+	v.log.Debugf("Received byte 0x%02X", data[0])
+	return data[0], nil
+}
@@ -0,0 +1,137 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestRegBlock(t *testing.T) {
+	v, a, _ := newFake(t, 0x0B)
+	c := a.chip(0x0B)
+	full := make([]byte, 32)
+	for i := range full {
+		full[i] = byte(0xA0 + i)
+	}
+	for _, data := range [][]byte{{}, full} {
+		if err := v.WriteRegBlock(0x20, data); err != nil {
+			t.Fatal(err)
+		}
+		// Count byte followed by data.
+		if c.regs[0x20] != byte(len(data)) || !bytes.Equal(c.regs[0x21:0x21+len(data)], data) {
+			t.Errorf("block of %d bytes stored as [% X]", len(data), c.regs[0x20:0x21+len(data)])
+		}
+		got, err := v.ReadRegBlock(0x20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(data) || !bytes.Equal(got, data) {
+			t.Errorf("read block [% X], want [% X]", got, data)
+		}
+	}
+	if err := v.WriteRegBlock(0x20, make([]byte, 33)); err == nil {
+		t.Error("33 bytes block write accepted")
+	}
+	for _, op := range a.operations("smbus") {
+		if op.cmd != I2C_SMBUS_BLOCK_DATA || op.arg != 0x20 {
+			t.Errorf("unexpected SMBus transfer %+v", op)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	v, a, _ := newFake(t, 0x0B)
+	quietLog(t)
+	c := a.chip(0x0B)
+	c.ptr = 0x42
+	if ok, err := v.Probe(); !ok || err != nil {
+		t.Fatalf("Probe = %v, %v", ok, err)
+	}
+	// Quick command doesn't touch register pointer.
+	if c.ptr != 0x42 {
+		t.Errorf("register pointer moved to 0x%02X", c.ptr)
+	}
+	if ops := a.operations(); len(ops) != 1 || ops[0].cmd != I2C_SMBUS_QUICK {
+		t.Errorf("operations %+v, want one quick command", ops)
+	}
+
+	// Absent device: NAK (ENXIO) or ENODEV.
+	for _, errno := range []error{syscall.ENXIO, syscall.ENODEV} {
+		a.failNext(errno)
+		if ok, err := v.Probe(); ok || err != nil {
+			t.Errorf("Probe on %v = %v, %v, want false, nil", errno, ok, err)
+		}
+	}
+	if err := v.SetAddr(0x0C); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := v.Probe(); ok || err != nil {
+		t.Errorf("Probe of empty address = %v, %v, want false, nil", ok, err)
+	}
+
+	// Other failures are reported.
+	a.failNext(syscall.ETIMEDOUT)
+	if ok, err := v.Probe(); ok || !errors.Is(err, syscall.ETIMEDOUT) {
+		t.Errorf("Probe on ETIMEDOUT = %v, %v, want false, ETIMEDOUT", ok, err)
+	}
+
+	// Quick command not supported: fall back to one byte read.
+	if err := v.SetAddr(0x0B); err != nil {
+		t.Fatal(err)
+	}
+	a.reset()
+	a.failNext(syscall.EOPNOTSUPP)
+	if ok, err := v.Probe(); !ok || err != nil {
+		t.Fatalf("Probe with read = %v, %v", ok, err)
+	}
+	if ops := a.operations("read"); len(ops) != 1 || len(ops[0].data) != 1 {
+		t.Errorf("reads %+v, want one byte read", ops)
+	}
+}
+
+func TestSmbusPrimitives(t *testing.T) {
+	v, a, _ := newFake(t, 0x0B)
+	c := a.chip(0x0B)
+	c.regs[0x37] = 0x5C
+	type union struct {
+		readWrite uint16
+		command   uintptr
+		size      uintptr
+	}
+	tests := []struct {
+		name string
+		op   func() error
+		want union
+	}{
+		{"QuickWrite(false)", func() error { return v.QuickWrite(false) },
+			union{I2C_SMBUS_WRITE, 0, I2C_SMBUS_QUICK}},
+		{"QuickWrite(true)", func() error { return v.QuickWrite(true) },
+			union{I2C_SMBUS_READ, 0, I2C_SMBUS_QUICK}},
+		// Byte to send travels in command field; fake chip takes
+		// it as register pointer, which ReceiveByte then reads.
+		{"SendByte", func() error { return v.SendByte(0x37) },
+			union{I2C_SMBUS_WRITE, 0x37, I2C_SMBUS_BYTE}},
+		{"ReceiveByte", func() error {
+			b, err := v.ReceiveByte()
+			if err == nil && b != 0x5C {
+				t.Errorf("ReceiveByte = 0x%02X, want 0x5C", b)
+			}
+			return err
+		}, union{I2C_SMBUS_READ, 0, I2C_SMBUS_BYTE}},
+	}
+	for _, test := range tests {
+		a.reset()
+		if err := test.op(); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		ops := a.operations()
+		if len(ops) != 1 || ops[0].kind != "smbus" {
+			t.Fatalf("%s: operations %+v, want one SMBus transfer", test.name, ops)
+		}
+		got := union{ops[0].flags, ops[0].arg, ops[0].cmd}
+		if got != test.want {
+			t.Errorf("%s: union %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package i2c
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Snapshot reads n consecutive registers from I2C-device starting
+// from start address, under connection lock for consistency,
+// and returns map from register address to its value.
+// Handy to compare device state before and after an operation.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) Snapshot(start byte, n int) (map[byte]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.snapshot(start, n)
+}
+
+// snapshot is Snapshot, which doesn't acquire connection lock.
+func (v *I2C) snapshot(start byte, n int) (map[byte]byte, error) {
+	if n < 0 || int(start)+n > 0x100 {
+		return nil, fmt.Errorf("register range 0x%0X+%d exceeds 0xFF", start, n)
+	}
+	buf, _, err := v.readRegBytes(start, n)
+	if err != nil {
+		return nil, err
+	}
+	regs := make(map[byte]byte, n)
+	for i, b := range buf {
+		regs[start+byte(i)] = b
+	}
+	return regs, nil
+}
+
+// Fingerprint reads identity/configuration registers listed in regs
+// from I2C-device and returns stable hash (SHA-256) over register
+// addresses and their values. Comparing fingerprints taken before
+// and after reconnect detects hot-swap: different physical device
+// now at the same address.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) Fingerprint(regs []byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	h := sha256.New()
+	for _, reg := range regs {
+		b, err := v.readRegU8(reg)
+		if err != nil {
+			return nil, err
+		}
+		h.Write([]byte{reg, b})
+	}
+	return h.Sum(nil), nil
+}
+
+// snapshotDoc is a JSON representation of device state snapshot.
+type snapshotDoc struct {
+	Bus       int               `json:"bus"`
+	Address   string            `json:"address"`
+	Timestamp time.Time         `json:"timestamp"`
+	Registers map[string]string `json:"registers"`
+}
+
+// SnapshotJSON reads n consecutive registers from I2C-device starting
+// from start address and returns JSON document, suitable to attach
+// to bug report, in format:
+//
+//	{
+//	  "bus": 1,
+//	  "address": "0x76",
+//	  "timestamp": "2018-05-01T12:00:00Z",
+//	  "registers": {"0xD0": "0x58", "0xD1": "0x00"}
+//	}
+//
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) SnapshotJSON(start byte, n int) ([]byte, error) {
+	// Address must match registers read.
+	v.mu.Lock()
+	regs, err := v.snapshot(start, n)
+	addr := v.GetAddr16()
+	v.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	doc := snapshotDoc{Bus: v.bus, Address: fmt.Sprintf("0x%02X", addr),
+		Timestamp: v.clock.Now(), Registers: make(map[string]string, len(regs))}
+	for reg, b := range regs {
+		doc.Registers[fmt.Sprintf("0x%02X", reg)] = fmt.Sprintf("0x%02X", b)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// DiffSnapshots compare two register snapshots (see Snapshot) and
+// returns registers, which values differ, with their values before
+// (from a) and after (from b). Registers missing in either
+// snapshot are ignored. No bus access is made.
+func DiffSnapshots(a, b map[byte]byte) map[byte][2]byte {
+	diff := make(map[byte][2]byte)
+	for reg, before := range a {
+		after, ok := b[reg]
+		if ok && after != before {
+			diff[reg] = [2]byte{before, after}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,116 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	for i := 0; i < 4; i++ {
+		c.regs[0xF0+i] = byte(0xA0 + i)
+	}
+	regs, err := v.Snapshot(0xF0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regs) != 4 {
+		t.Errorf("%d registers in snapshot, want 4", len(regs))
+	}
+	for i := 0; i < 4; i++ {
+		if b, ok := regs[byte(0xF0+i)]; !ok || b != byte(0xA0+i) {
+			t.Errorf("reg 0x%02X: 0x%02X, %v", 0xF0+i, b, ok)
+		}
+	}
+	if _, err := v.Snapshot(0xF0, 17); err == nil {
+		t.Error("range beyond 0xFF accepted")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	c.regs[0xD0] = 0x58
+	c.regs[0xF4] = 0x27
+	regs := []byte{0xD0, 0xF4}
+	fp1, err := v.Fingerprint(regs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp2, err := v.Fingerprint(regs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fp1, fp2) {
+		t.Error("same registers give different fingerprints")
+	}
+	// Different device at the same address.
+	c.regs[0xD0] = 0x60
+	fp3, err := v.Fingerprint(regs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(fp1, fp3) {
+		t.Error("different register values give the same fingerprint")
+	}
+	// Register set is part of fingerprint.
+	fp4, err := v.Fingerprint([]byte{0xD0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(fp3, fp4) {
+		t.Error("different register sets give the same fingerprint")
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	v, a, _ := newFake(t, 0x76)
+	copy(a.chip(0x76).regs[0xD0:], []byte{0x58, 0x00, 0x0A})
+	data, err := v.SnapshotJSON(0xD0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	want := map[string]interface{}{
+		"bus":       1.0,
+		"address":   "0x76",
+		"timestamp": "2020-01-01T00:00:00Z",
+		"registers": map[string]interface{}{"0xD0": "0x58", "0xD1": "0x00", "0xD2": "0x0A"},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %s, want %v", data, want)
+	}
+}
+
+func TestSnapshotJSONTenBit(t *testing.T) {
+	v, _ := newTenBitFake(t, 0x3A5)
+	data, err := v.SnapshotJSON(0x00, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc snapshotDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if doc.Address != "0x3A5" {
+		t.Errorf("address %q, want \"0x3A5\"", doc.Address)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := map[byte]byte{0x00: 0x10, 0x01: 0x20, 0x02: 0x30, 0x03: 0x40}
+	after := map[byte]byte{0x00: 0x10, 0x01: 0x21, 0x02: 0x30, 0x03: 0x00, 0x04: 0x55}
+	want := map[byte][2]byte{0x01: {0x20, 0x21}, 0x03: {0x40, 0x00}}
+	if diff := DiffSnapshots(before, after); !reflect.DeepEqual(diff, want) {
+		t.Errorf("got %X, want %X", diff, want)
+	}
+	if diff := DiffSnapshots(before, before); len(diff) != 0 {
+		t.Errorf("equal snapshots differ: %X", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package i2c
+
+import "sync"
+
+// lastErrors keep the most recent failure of each operation type.
+type lastErrors struct {
+	mu    sync.Mutex
+	read  error
+	write error
+}
+
+// LastReadError return the most recent error of read
+// operation on I2C-connection, or nil if none happened.
+func (v *I2C) LastReadError() error {
+	v.lastErr.mu.Lock()
+	defer v.lastErr.mu.Unlock()
+	return v.lastErr.read
+}
+
+// LastWriteError return the most recent error of write
+// operation on I2C-connection, or nil if none happened.
+func (v *I2C) LastWriteError() error {
+	v.lastErr.mu.Lock()
+	defer v.lastErr.mu.Unlock()
+	return v.lastErr.write
+}
+
+func (v *I2C) setReadError(err error) {
+	v.lastErr.mu.Lock()
+	v.lastErr.read = err
+	v.lastErr.mu.Unlock()
+}
+
+func (v *I2C) setWriteError(err error) {
+	v.lastErr.mu.Lock()
+	v.lastErr.write = err
+	v.lastErr.mu.Unlock()
+}
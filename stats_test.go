@@ -0,0 +1,32 @@
+package i2c
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestLastErrors(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	if v.LastReadError() != nil || v.LastWriteError() != nil {
+		t.Fatal("errors recorded before any failure")
+	}
+	a.failNext(syscall.EIO)
+	if err := v.WriteRegU8(0x01, 0x02); err == nil {
+		t.Fatal("write failure not reported")
+	}
+	if !errors.Is(v.LastWriteError(), syscall.EIO) || v.LastReadError() != nil {
+		t.Errorf("after write failure: read %v, write %v",
+			v.LastReadError(), v.LastWriteError())
+	}
+	// Register address write succeeds, read fails.
+	a.failNext(nil, syscall.ETIMEDOUT)
+	if _, err := v.ReadRegU8(0x01); err == nil {
+		t.Fatal("read failure not reported")
+	}
+	if !errors.Is(v.LastReadError(), syscall.ETIMEDOUT) ||
+		!errors.Is(v.LastWriteError(), syscall.EIO) {
+		t.Errorf("after read failure: read %v, write %v",
+			v.LastReadError(), v.LastWriteError())
+	}
+}
@@ -0,0 +1,59 @@
+package i2c
+
+import "sort"
+
+// CheckAndClearErrors reads status register statusReg from I2C-device,
+// collects names of error bits set (errBits maps bit mask to its name)
+// and, if any found, clears them writing clearVal to clearReg.
+// Returns names of error bits found, ordered by bit mask.
+// Standardizes handling of chips with sticky error flags.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) CheckAndClearErrors(statusReg byte, errBits map[byte]string,
+	clearReg byte, clearVal byte) ([]string, error) {
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	status, err := v.readRegU8(statusReg)
+	if err != nil {
+		return nil, err
+	}
+	masks := make([]int, 0, len(errBits))
+	for mask := range errBits {
+		if status&mask != 0 {
+			masks = append(masks, int(mask))
+		}
+	}
+	if len(masks) == 0 {
+		return nil, nil
+	}
+	sort.Ints(masks)
+	names := make([]string, len(masks))
+	for i, mask := range masks {
+		names[i] = errBits[byte(mask)]
+	}
+	v.log.Debugf("Error flags set in status 0x%0X: %v", status, names)
+	if err := v.writeRegU8(clearReg, clearVal); err != nil {
+		return names, err
+	}
+	return names, nil
+}
+
+// ReadRegFlags reads byte from I2C-device register specified in reg
+// and interprets it as a set of named flags: names maps bit positions
+// (0..7) to flag names. Returns map of flag name to bit state.
+// Unnamed bits are ignored.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) ReadRegFlags(reg byte, names map[uint]string) (map[string]bool, error) {
+	b, err := v.ReadRegU8(reg)
+	if err != nil {
+		return nil, err
+	}
+	flags := make(map[string]bool, len(names))
+	for bit, name := range names {
+		if bit > 7 {
+			continue
+		}
+		flags[name] = b&(1<<bit) != 0
+	}
+	return flags, nil
+}
@@ -0,0 +1,50 @@
+package i2c
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCheckAndClearErrors(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	errBits := map[byte]string{0x01: "OVF", 0x04: "CRC", 0x10: "UVLO", 0x80: "OTP"}
+	c.regs[0x02] = 0x95
+	names, err := v.CheckAndClearErrors(0x02, errBits, 0x03, 0xFF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"OVF", "CRC", "UVLO", "OTP"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+	w := a.writes()
+	if len(w) == 0 || !bytes.Equal(w[len(w)-1], []byte{0x03, 0xFF}) {
+		t.Errorf("clear write missing: %X", w)
+	}
+
+	// No error bits set: nothing cleared.
+	a.reset()
+	c.regs[0x02] = 0x42
+	names, err = v.CheckAndClearErrors(0x02, errBits, 0x03, 0xFF)
+	if err != nil || names != nil {
+		t.Errorf("got %v, %v, want no errors", names, err)
+	}
+	if w := a.writes(); len(w) != 1 {
+		t.Errorf("unexpected writes: %X", w)
+	}
+}
+
+func TestReadRegFlags(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	a.chip(0x40).regs[0x0F] = 0x85
+	names := map[uint]string{0: "READY", 1: "BUSY", 2: "OVF", 7: "ALERT", 9: "BOGUS"}
+	flags, err := v.ReadRegFlags(0x0F, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"READY": true, "BUSY": false, "OVF": true, "ALERT": true}
+	if !reflect.DeepEqual(flags, want) {
+		t.Errorf("got %v, want %v", flags, want)
+	}
+}
@@ -0,0 +1,100 @@
+package i2c
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// regReader implements io.ReadCloser over register stream.
+type regReader struct {
+	i2c    *I2C
+	tx     uint64
+	err    error
+	closed bool
+}
+
+// RegReader returns io.ReadCloser, positioned at register reg: register
+// address is written once, and then each Read pulls next bytes from
+// I2C-device (which must auto-increment register pointer), so register
+// stream can be passed to io.Copy or wrapped in bufio.Reader.
+//
+// Reader holds connection lock until Close, to keep register pointer
+// intact, so Close must always be called. Error of register address
+// write is reported by the first Read.
+func (v *I2C) RegReader(reg byte) io.ReadCloser {
+	v.mu.Lock()
+	r := &regReader{i2c: v, tx: nextTx()}
+	r.err = v.selectReg(r.tx, reg)
+	return r
+}
+
+func (r *regReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errors.New("read from closed register reader")
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.i2c.readBytes(r.tx, p)
+}
+
+// Close release connection lock.
+func (r *regReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.i2c.mu.Unlock()
+	return nil
+}
+
+// DecodeStream continuously reads samples of sampleBytes (1..4) from
+// I2C-device register reg, decodes them with byte order specified
+// in order (sign-extended, if signed) and emits them on returned
+// samples channel, until ctx is done. Read error is sent to errors
+// channel and stops stream. Both channels are closed, when stream
+// stops. Reads are not paced: device is expected to block or pacing
+// is done by consumer speed.
+// SMBus (System Management Bus) protocol over I2C.
+func (v *I2C) DecodeStream(ctx context.Context, reg byte, sampleBytes int,
+	order binary.ByteOrder, signed bool) (<-chan int32, <-chan error) {
+
+	samples := make(chan int32)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(samples)
+		defer close(errs)
+		if sampleBytes < 1 || sampleBytes > 4 {
+			errs <- fmt.Errorf("sample size %d out of range 1..4", sampleBytes)
+			return
+		}
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			v.mu.Lock()
+			u, err := v.readRegUint(reg, sampleBytes, order)
+			v.mu.Unlock()
+			if err != nil {
+				errs <- err
+				return
+			}
+			s := int32(u)
+			if signed {
+				s = int32(signExtend(u, uint(sampleBytes)*8))
+			}
+			select {
+			case samples <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return samples, errs
+}
@@ -0,0 +1,73 @@
+package i2c
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestRegReader(t *testing.T) {
+	v, a, _ := newFake(t, 0x50)
+	seq := []byte("0123456789abcdef")
+	copy(a.chip(0x50).regs[0x40:], seq)
+	r := v.RegReader(0x40)
+	got := make([]byte, len(seq))
+	if _, err := io.ReadFull(bufio.NewReaderSize(r, 16), got); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, seq) {
+		t.Errorf("read %q, want %q", got, seq)
+	}
+	// Register pointer written once.
+	if w := a.writes(); len(w) != 1 || !bytes.Equal(w[0], []byte{0x40}) {
+		t.Errorf("unexpected writes: %X", w)
+	}
+	if _, err := r.Read(got); err == nil {
+		t.Error("read from closed reader succeeded")
+	}
+	// Lock released by Close.
+	if _, err := v.ReadRegU8(0x40); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	v, a, _ := newFake(t, 0x48)
+	c := a.chip(0x48)
+	want := []int32{-1, 0, 300, -32768, 32767}
+	n := 0
+	c.onRead = func(c *fakeChip) {
+		binary.BigEndian.PutUint16(c.regs[0x00:], uint16(want[n%len(want)]))
+		n++
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	samples, errs := v.DecodeStream(ctx, 0x00, 2, binary.BigEndian, true)
+	for i, w := range want {
+		if s := <-samples; s != w {
+			t.Errorf("sample %d: got %d, want %d", i, s, w)
+		}
+	}
+	cancel()
+	// Both channels closed after cancellation.
+	for range samples {
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	c.onRead = nil
+	c.nak = 1
+	samples, errs = v.DecodeStream(context.Background(), 0x00, 2, binary.BigEndian, true)
+	if err := <-errs; err == nil {
+		t.Error("read failure not reported")
+	}
+	if _, ok := <-samples; ok {
+		t.Error("samples channel not closed after error")
+	}
+}
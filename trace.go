@@ -0,0 +1,13 @@
+package i2c
+
+import "sync/atomic"
+
+// txCounter is a source of transaction IDs, shown in debug log,
+// so write and read lines of the same transaction (register read,
+// for instance) can be correlated in interleaved multi-device logs.
+var txCounter uint64
+
+// nextTx return new unique transaction ID.
+func nextTx() uint64 {
+	return atomic.AddUint64(&txCounter, 1)
+}
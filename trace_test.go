@@ -0,0 +1,75 @@
+package i2c
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// captureLogger keeps formatted log lines for inspection.
+type captureLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) add(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Debugf(format string, args ...interface{}) { l.add("DEBUG", format, args...) }
+func (l *captureLogger) Infof(format string, args ...interface{})  { l.add("INFO", format, args...) }
+func (l *captureLogger) Errorf(format string, args ...interface{}) { l.add("ERROR", format, args...) }
+
+func (l *captureLogger) output() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+// captureLog route package log output to returned captureLogger
+// at debug level until test end.
+func captureLog(t testing.TB) *captureLogger {
+	out, level := lg.get()
+	l := &captureLogger{}
+	SetLogger(l)
+	SetLogLevel(DebugLevel)
+	t.Cleanup(func() {
+		SetLogger(out)
+		SetLogLevel(level)
+	})
+	return l
+}
+
+var txRe = regexp.MustCompile(`\[tx (\d+)\]`)
+
+func TestTransactionID(t *testing.T) {
+	v, _, _ := newFake(t, 0x40)
+	l := captureLog(t)
+	if _, err := v.ReadRegU8(0x10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.ReadRegU8(0x11); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, line := range l.output() {
+		if m := txRe.FindStringSubmatch(line); m != nil {
+			ids = append(ids, m[1])
+		}
+	}
+	// Register address write, data read and result line per transaction.
+	if len(ids) != 6 {
+		t.Fatalf("expected 6 traced lines, got %q", l.output())
+	}
+	for i := 1; i < 3; i++ {
+		if ids[i] != ids[0] || ids[3+i] != ids[3] {
+			t.Errorf("lines of one transaction carry different IDs: %q", l.output())
+		}
+	}
+	if ids[0] == ids[3] {
+		t.Errorf("two transactions share ID %s", ids[0])
+	}
+}
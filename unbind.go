@@ -0,0 +1,35 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// sysfsDrivers is a sysfs directory of I2C kernel drivers.
+var sysfsDrivers = "/sys/bus/i2c/drivers"
+
+// NewUnbinding opens a connection for I2C-device, like NewI2C does,
+// but if address is claimed by kernel driver (EBUSY), unbind device
+// from driver via sysfs (writing device name, like "1-0068", to
+// /sys/bus/i2c/drivers/<driver>/unbind) and retry.
+//
+// Use with care: device stays unbound from kernel driver until it is
+// bound back (or system reboot), so kernel services using it stop
+// working. Requires root privileges.
+func NewUnbinding(addr uint8, bus int, driver string, opts ...Option) (*I2C, error) {
+	v, err := NewI2C(addr, bus, opts...)
+	if err == nil || !errors.Is(err, syscall.EBUSY) {
+		return v, err
+	}
+	name := fmt.Sprintf("%d-%04x", bus, addr)
+	path := filepath.Join(sysfsDrivers, driver, "unbind")
+	lg.Infof("Address 0x%0X on bus %d busy, unbind %s from driver %q",
+		addr, bus, name, driver)
+	if err := os.WriteFile(path, []byte(name), 0200); err != nil {
+		return nil, fmt.Errorf("can't unbind %s from driver %q: %w", name, driver, err)
+	}
+	return NewI2C(addr, bus, opts...)
+}
@@ -0,0 +1,53 @@
+package i2c
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestNewUnbinding(t *testing.T) {
+	a := newFakeAdapter()
+	a.chip(0x68)
+	a.busy[0x68] = true
+	installFakeBuses(t, map[int]*fakeAdapter{1: a})
+
+	drivers := sysfsDrivers
+	t.Cleanup(func() { sysfsDrivers = drivers })
+	sysfsDrivers = t.TempDir()
+	unbind := filepath.Join(sysfsDrivers, "rtc-ds1307", "unbind")
+	if err := os.MkdirAll(filepath.Dir(unbind), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unbind, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Kernel release address, once device is unbound.
+	open := openDevice
+	openDevice = func(path string) (device, error) {
+		if b, _ := os.ReadFile(unbind); string(b) == "1-0068" {
+			delete(a.busy, 0x68)
+		}
+		return open(path)
+	}
+
+	if _, err := NewI2C(0x68, 1); !errors.Is(err, syscall.EBUSY) {
+		t.Fatalf("expected EBUSY, got %v", err)
+	}
+	v, err := NewUnbinding(0x68, 1, "rtc-ds1307")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if b, _ := os.ReadFile(unbind); string(b) != "1-0068" {
+		t.Errorf("unbind written with %q, want \"1-0068\"", b)
+	}
+
+	// Unknown driver.
+	a.busy[0x69] = true
+	if _, err := NewUnbinding(0x69, 1, "missing"); err == nil {
+		t.Error("unbind from missing driver succeeded")
+	}
+}
@@ -0,0 +1,64 @@
+package i2c
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// isNak return true if err signals, that I2C-device
+// hasn't acknowledged transfer.
+func isNak(err error) bool {
+	return errors.Is(err, syscall.ENXIO) || errors.Is(err, syscall.EREMOTEIO) ||
+		errors.Is(err, syscall.EIO)
+}
+
+// autoWake track idle time to wake up device before access.
+type autoWake struct {
+	idle time.Duration
+	mu   sync.Mutex
+	last time.Time
+}
+
+// WakeUp send dummy wake-up transaction (address only, zero-length
+// write) to low-power I2C-device, which needs it before it respond.
+// Sleeping device is expected to NAK it: such NAK isn't treated as
+// failure, so only other errors are returned. Adapter must support
+// I2C_FUNC_I2C functionality.
+func (v *I2C) WakeUp() error {
+	v.log.Debugf("Wake up device at address 0x%0X", v.addr)
+	err := v.Exec(NewTransaction().Write(nil, 0))
+	if err != nil && !isNak(err) {
+		return err
+	}
+	return nil
+}
+
+// WithAutoWakeUp make connection send wake-up transaction (see WakeUp)
+// automatically before first access after device has been idle
+// for longer than idle (and before the very first access).
+func WithAutoWakeUp(idle time.Duration) Option {
+	return func(v *I2C) error {
+		v.wake = &autoWake{idle: idle}
+		return nil
+	}
+}
+
+// wakeIfIdle wake device up, if it has been idle long enough.
+func (v *I2C) wakeIfIdle() {
+	w := v.wake
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	now := v.clock.Now()
+	idle := w.last.IsZero() || now.Sub(w.last) > w.idle
+	w.last = now
+	w.mu.Unlock()
+	if idle {
+		if err := v.WakeUp(); err != nil {
+			v.log.Debugf("Wake up failed: %v", err)
+		}
+	}
+}
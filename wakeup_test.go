@@ -0,0 +1,66 @@
+package i2c
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+// wakeUps return number of acknowledged wake-up
+// transactions recorded by adapter.
+func wakeUps(a *fakeAdapter) int {
+	n := 0
+	for _, op := range a.operations("write") {
+		if op.rdwr && len(op.data) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWakeUp(t *testing.T) {
+	v, a, _ := newFake(t, 0x40)
+	c := a.chip(0x40)
+	c.regs[0x00] = 0x5A
+	// Sleeping device NAKs wake-up transaction.
+	c.nak = 1
+	if err := v.WakeUp(); err != nil {
+		t.Fatalf("expected NAK ignored, got %v", err)
+	}
+	if c.nak != 0 {
+		t.Fatal("wake-up transaction not sent")
+	}
+	if b, err := v.ReadRegU8(0x00); err != nil || b != 0x5A {
+		t.Errorf("read after wake-up = 0x%02X, %v", b, err)
+	}
+
+	a.failNext(syscall.EBUSY)
+	if err := v.WakeUp(); err == nil {
+		t.Error("non-NAK failure ignored")
+	}
+}
+
+func TestAutoWakeUp(t *testing.T) {
+	v, a, clock := newFake(t, 0x40, WithAutoWakeUp(time.Second))
+	c := a.chip(0x40)
+	// First access wakes device up, so NAK
+	// doesn't reach register read.
+	c.nak = 1
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	if n := wakeUps(a); n != 0 {
+		t.Errorf("%d wake-ups before idle period, want 0", n)
+	}
+	clock.Advance(2 * time.Second)
+	if _, err := v.ReadRegU8(0x00); err != nil {
+		t.Fatal(err)
+	}
+	if n := wakeUps(a); n != 1 {
+		t.Errorf("%d wake-ups after idle period, want 1", n)
+	}
+}